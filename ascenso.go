@@ -26,6 +26,12 @@ func Log(w http.ResponseWriter, r *http.Request) {
 	log.HandleRequest(context.Background(), w, r)
 }
 
+// Scoreboard is the entry point into the "Scoreboard" Cloud Function.
+// The actual implementation lives in the admin package.
+func Scoreboard(w http.ResponseWriter, r *http.Request) {
+	admin.HandleScoreboardRequest(context.Background(), w, r)
+}
+
 // Test is the entry point into the "Test" Cloud Function.
 // The actual implementation lives in the test package.
 func Test(w http.ResponseWriter, r *http.Request) {
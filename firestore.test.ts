@@ -33,6 +33,14 @@ const userPath = `users/${uid}`;
 const teamPath = `teams/${team}`;
 const invitePath = `invites/${invite}`;
 
+// Paths within a non-default competition.
+const comp = 'comp-id';
+const compPrefix = `competitions/${comp}/`;
+const compConfigPath = compPrefix + configPath;
+const compUserPath = compPrefix + userPath;
+const compTeamPath = compPrefix + teamPath;
+const compInvitePath = compPrefix + invitePath;
+
 const otherName = 'Other Name';
 const otherTeam = 'other-team-id';
 const otherInvite = '999999';
@@ -397,6 +405,28 @@ describe('Firestore', () => {
     await deny(batch.commit());
   });
 
+  it('allows joining larger team when team size is configured', async () => {
+    await writeDocs(State.EMPTY_TEAM);
+    await adminDB.doc(configPath).set({ teamSize: 3 });
+    await adminDB.doc(teamPath).update({ users: { a: {}, b: {} } });
+
+    const batch = authDB.batch();
+    batch.update(authDB.doc(userPath), { team });
+    batch.update(authDB.doc(teamPath), { [`users.${uid}`]: { name, climbs } });
+    await allow(batch.commit());
+  });
+
+  it('denies joining team when configured team size is reached', async () => {
+    await writeDocs(State.EMPTY_TEAM);
+    await adminDB.doc(configPath).set({ teamSize: 1 });
+    await adminDB.doc(teamPath).update({ users: { a: {} } });
+
+    const batch = authDB.batch();
+    batch.update(authDB.doc(userPath), { team });
+    batch.update(authDB.doc(teamPath), { [`users.${uid}`]: { name, climbs } });
+    await deny(batch.commit());
+  });
+
   it('denies joining team without updating team doc', async () => {
     await writeDocs(State.EMPTY_TEAM);
     await deny(authDB.doc(userPath).update({ team }));
@@ -470,4 +500,55 @@ describe('Firestore', () => {
       })
     );
   });
+
+  it('allows anonymous read-only access to competition config doc', async () => {
+    const ref = anonDB.doc(compConfigPath);
+    await allow(ref.get());
+    await deny(ref.set({}));
+  });
+
+  it('denies access to competition auth doc', async () => {
+    await deny(authDB.doc(compPrefix + authPath).get());
+  });
+
+  it('allows creating team in competition', async () => {
+    await adminDB.doc(compUserPath).set({ name });
+    const batch = authDB.batch();
+    batch.update(authDB.doc(compUserPath), { team });
+    batch.set(authDB.doc(compTeamPath), { name, invite, users });
+    batch.set(authDB.doc(compInvitePath), { team });
+    await allow(batch.commit());
+  });
+
+  it('denies creating team in competition with default invite doc', async () => {
+    await adminDB.doc(compUserPath).set({ name });
+    const batch = authDB.batch();
+    batch.update(authDB.doc(compUserPath), { team });
+    batch.set(authDB.doc(compTeamPath), { name, invite, users });
+    batch.set(authDB.doc(invitePath), { team });
+    await deny(batch.commit());
+  });
+
+  it('uses competition readonly setting', async () => {
+    await adminDB.doc(configPath).set({ readonly: true });
+    await allow(authDB.doc(compUserPath).set({ name }));
+    await adminDB.doc(compConfigPath).set({ readonly: true });
+    await deny(authDB.doc(compUserPath).set({ name }));
+  });
+
+  it('uses competition team size', async () => {
+    await adminDB.doc(configPath).set({ teamSize: 1 });
+    await adminDB.doc(compConfigPath).set({ teamSize: 3 });
+    await adminDB.doc(compUserPath).set({ name });
+    await adminDB
+      .doc(compTeamPath)
+      .set({ name, invite, users: { a: {}, b: {} } });
+
+    const batch = authDB.batch();
+    batch.update(authDB.doc(compUserPath), { team });
+    batch.update(authDB.doc(compTeamPath), {
+      [`users.${uid}`]: { name, climbs },
+    });
+    await allow(batch.commit());
+  });
 });
@@ -9,7 +9,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
+	"html/template"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -25,54 +26,125 @@ const maxRequestBytes = 10 << 20 // memory for parsing HTTP requests
 func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		fmt.Fprint(w, strings.TrimLeft(getHTML, "\n"))
+		loc := newLocalizer(r)
+		if err := writeAdminPage(w, loc); err != nil {
+			http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
+		}
 	case http.MethodPost:
 		if err := r.ParseMultipartForm(maxRequestBytes); err != nil {
-			http.Error(w, "Failed parsing form data", http.StatusBadRequest)
+			http.Error(w, newLocalizer(r).T("Failed parsing form data"), http.StatusBadRequest)
 			return
 		}
+		loc := newLocalizer(r)
 
 		// Initialize Cloud Firestore.
 		client, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT")) // set at deployment
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed creating Firestore client: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed creating Firestore client: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		// Check that the request is authorized.
 		if ok, err := checkPassword(ctx, client, r.FormValue("password")); err != nil {
-			http.Error(w, fmt.Sprintf("Failed checking password: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed checking password: %v", err), http.StatusInternalServerError)
 			return
 		} else if !ok {
-			http.Error(w, "Incorrect password", http.StatusUnauthorized)
+			http.Error(w, loc.T("Incorrect password"), http.StatusUnauthorized)
+			return
+		}
+
+		// All actions operate on a single competition.
+		comp := r.FormValue("competition")
+		if !db.ValidCompetitionID(comp) {
+			http.Error(w, loc.T("Bad competition %q", comp), http.StatusBadRequest)
 			return
 		}
 
 		action := r.FormValue("action")
 		switch action {
+		case "areaStats":
+			handleAreaStats(ctx, w, r, client, comp)
+		case "areasCsv":
+			handlePostAreasCSV(ctx, w, r, client, comp)
+		case "approveClimb":
+			handleApproveClimb(ctx, w, r, client, comp)
+		case "archive":
+			handleArchive(ctx, w, r, client, comp)
+		case "archives":
+			handleListArchives(ctx, w, r, client, comp)
+		case "awardsPdf":
+			handleAwardsPDF(ctx, w, r, client, comp)
 		case "clearScores":
-			handleClearScores(ctx, w, r, client)
+			handleClearScores(ctx, w, r, client, comp)
 		case "emptyTeams":
-			handleEmptyTeams(ctx, w, r, client)
+			handleEmptyTeams(ctx, w, r, client, comp)
+		case "eraseUser":
+			handleEraseUser(ctx, w, r, client, comp)
+		case "exportUser":
+			handleExportUser(ctx, w, r, client, comp)
+		case "fsck":
+			handleFsck(ctx, w, r, client, comp)
+		case "listTeams":
+			handleListTeams(ctx, w, r, client, comp)
+		case "mergeTeams":
+			handleMergeTeams(ctx, w, r, client, comp)
+		case "mountainProject":
+			handleMountainProject(ctx, w, r, client, comp)
+		case "moveUser":
+			handleMoveUser(ctx, w, r, client, comp)
+		case "pendingClimbs":
+			handlePendingClimbs(ctx, w, r, client, comp)
 		case "readonly":
-			handleReadonly(ctx, w, r, client)
+			handleReadonly(ctx, w, r, client, comp)
+		case "rejectClimb":
+			handleRejectClimb(ctx, w, r, client, comp)
+		case "renameTeam":
+			handleRenameTeam(ctx, w, r, client, comp)
+		case "roster":
+			handleRoster(ctx, w, r, client, comp)
+		case "rotateInvite":
+			handleRotateInvite(ctx, w, r, client, comp)
+		case "routeStats":
+			handleRouteStats(ctx, w, r, client, comp)
+		case "routeStatsCsv":
+			handleRouteStatsCSV(ctx, w, r, client, comp)
 		case "routes":
-			handlePostRoutes(ctx, w, r, client)
+			handlePostRoutes(ctx, w, r, client, comp)
+		case "routesCsv":
+			handlePostRoutesCSV(ctx, w, r, client, comp)
+		case "scoreboard":
+			handleScoreboard(ctx, w, r, client, comp)
+		case "scorecard":
+			handleScorecard(ctx, w, r, client, comp)
+		case "scoresJson":
+			handleScoresJSON(ctx, w, r, client, comp)
 		case "scoresTeams":
-			handlePostScoresTeams(ctx, w, r, client)
+			handlePostScoresTeams(ctx, w, r, client, comp)
 		case "scoresTeamsCsv":
-			handlePostScoresTeamsCSV(ctx, w, r, client)
+			handlePostScoresTeamsCSV(ctx, w, r, client, comp)
 		case "scoresUsers":
-			handlePostScoresUsers(ctx, w, r, client)
+			handlePostScoresUsers(ctx, w, r, client, comp)
 		case "scoresUsersCsv":
-			handlePostScoresUsersCSV(ctx, w, r, client)
+			handlePostScoresUsersCSV(ctx, w, r, client, comp)
+		case "scoresXlsx":
+			handleScoresXLSX(ctx, w, r, client, comp)
+		case "setClimb":
+			handleSetClimb(ctx, w, r, client, comp)
+		case "splitTeam":
+			handleSplitTeam(ctx, w, r, client, comp)
+		case "teamSize":
+			handleTeamSize(ctx, w, r, client, comp)
+		case "updateStandings":
+			handleUpdateStandings(ctx, w, r, client, comp)
+		case "userClimbs":
+			handleUserClimbs(ctx, w, r, client, comp)
 		case "writable":
-			handleWritable(ctx, w, r, client)
+			handleWritable(ctx, w, r, client, comp)
 		default:
-			http.Error(w, fmt.Sprintf("Bad action %q", action), http.StatusBadRequest)
+			http.Error(w, loc.T("Bad action %q", action), http.StatusBadRequest)
 		}
 	default:
-		http.Error(w, fmt.Sprintf("Bad method %q", r.Method), http.StatusMethodNotAllowed)
+		http.Error(w, newLocalizer(r).T("Bad method %q", r.Method), http.StatusMethodNotAllowed)
 	}
 }
 
@@ -89,12 +161,33 @@ func checkPassword(ctx context.Context, client *firestore.Client, password strin
 	return data.Hash == hex.EncodeToString(sum[:]), nil
 }
 
-// HTML data returned for GET requests.
-const getHTML = `
+// writeAdminPage writes the HTML document returned for GET requests to w, localized using loc.
+func writeAdminPage(w io.Writer, loc *localizer) error {
+	tmpl, err := template.New("").Funcs(loc.funcs()).Parse(strings.TrimLeft(adminTemplate, "\n"))
+	if err != nil {
+		return err
+	}
+	type langOption struct {
+		Code, Name string
+		Selected   bool
+	}
+	var opts []langOption
+	for _, l := range langs {
+		opts = append(opts, langOption{l.code, l.name, l.code == loc.lang})
+	}
+	return tmpl.Execute(w, struct {
+		Langs  []langOption
+		Metres bool
+	}{opts, loc.metres})
+}
+
+// adminTemplate is used to write the HTML document returned for GET requests.
+// Its data must have Langs and Metres fields.
+const adminTemplate = `
 <!DOCTYPE html>
 <html>
   <head>
-    <title>Admin</title>
+    <title>{{T "Admin"}}</title>
     <style>
       body {
         font-family: Arial, Helvetica, sans-serif;
@@ -115,76 +208,345 @@ const getHTML = `
   </head>
   <body>
     <form enctype="multipart/form-data" method="POST">
-      <h1>Admin</h1>
+      <h1>{{T "Admin"}}</h1>
 
-      <p>A password must be supplied to perform any admin operations.</p>
+      <p>{{T "A password must be supplied to perform any admin operations."}}</p>
       <div class="input-row">
-        <span class="label">Password</span>
+        <span class="label">{{T "Password"}}</span>
         <input name="password" type="password" />
       </div>
 
-      <h2>View scores</h2>
-      <p>View per-team or per-user scoreboards.</p>
+      <p>
+        {{T "Leave the competition empty to use the default competition that is displayed by the web app."}}
+      </p>
       <div class="input-row">
-        <button name="action" value="scoresTeams" type="submit">Teams</button>
-        <button name="action" value="scoresUsers" type="submit">Users</button>
-        <button name="action" value="scoresTeamsCsv" type="submit">Teams (CSV)</button>
-        <button name="action" value="scoresUsersCsv" type="submit">Users (CSV)</button>
+        <span class="label">{{T "Competition"}}</span>
+        <input name="competition" type="text" autocomplete="off" />
       </div>
 
-      <h2>Update routes</h2>
       <p>
-        Upload new area and route data in CSV format and use it to replace the
-        existing data.
+        {{T "Results, scoreboards, and scorecards are written in the selected language, with heights in the selected units."}}
       </p>
       <div class="input-row">
-        <span class="label">Areas CSV</span>
+        <span class="label">{{T "Language"}}</span>
+        <select name="lang">
+{{- range .Langs}}
+          <option value="{{.Code}}"{{if .Selected}} selected{{end}}>{{.Name}}</option>
+{{- end}}
+        </select>
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Units"}}</span>
+        <select name="units">
+          <option value="ft">{{T "Feet"}}</option>
+          <option value="m"{{if .Metres}} selected{{end}}>{{T "Metres"}}</option>
+        </select>
+      </div>
+
+      <h2>{{T "View scores"}}</h2>
+      <p>
+        {{T "View per-team or per-user scoreboards. Scores are recomputed at most every 30 seconds unless updated explicitly. If an archive ID is supplied, the archived competition's final standings are displayed instead."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "Archive"}}</span>
+        <input name="archive" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="scoresTeams" type="submit">{{T "Teams"}}</button>
+        <button name="action" value="scoresUsers" type="submit">{{T "Users"}}</button>
+        <button name="action" value="scoresTeamsCsv" type="submit">{{T "Teams (CSV)"}}</button>
+        <button name="action" value="scoresUsersCsv" type="submit">{{T "Users (CSV)"}}</button>
+        <button name="action" value="scoresJson" type="submit">JSON</button>
+        <button name="action" value="scoresXlsx" type="submit">{{T "Spreadsheet (XLSX)"}}</button>
+      </div>
+      <div class="input-row">
+        <button name="action" value="updateStandings" type="submit">{{T "Update now"}}</button>
+      </div>
+
+      <h2>{{T "Statistics"}}</h2>
+      <p>
+        {{T "View per-route and per-area statistics for the current competition. Climbs of routes requiring verification are only counted once approved."}}
+      </p>
+      <div class="input-row">
+        <button name="action" value="areaStats" type="submit">{{T "Areas"}}</button>
+        <button name="action" value="routeStats" type="submit">{{T "Routes"}}</button>
+        <button name="action" value="routeStatsCsv" type="submit">{{T "Routes (CSV)"}}</button>
+      </div>
+
+      <h2>{{T "Scorecards"}}</h2>
+      <p>
+        {{T "View a printable list of a user's climbs grouped by area, or of the climbs of each member of a team identified by team ID or invite code."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "User"}}</span>
+        <input name="scorecardUser" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Team"}}</span>
+        <input name="scorecardTeam" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="scorecard" type="submit">{{T "Scorecard"}}</button>
+      </div>
+
+      <h2>{{T "Public scoreboard"}}</h2>
+      <p>
+        {{T "Allow the competition's scoreboard to be viewed without a password via the <code>Scoreboard</code> Cloud Function, e.g. on a big screen. Climbers' names can be abbreviated to their initials."}}
+      </p>
+      <div class="input-row">
+        <input id="scoreboardPublic" name="scoreboardPublic" value="1" type="checkbox">
+        <label for="scoreboardPublic">{{T "Public"}}</label>
+      </div>
+      <div class="input-row">
+        <input id="scoreboardAnonymize" name="scoreboardAnonymize" value="1" type="checkbox">
+        <label for="scoreboardAnonymize">{{T "Show initials only"}}</label>
+      </div>
+      <div class="input-row">
+        <button name="action" value="scoreboard" type="submit">{{T "Update scoreboard"}}</button>
+      </div>
+
+      <h2>{{T "Awards"}}</h2>
+      <p>
+        {{T "Download a PDF with the top teams and the top climbers in each category, followed by a certificate for each listed climber. Tied climbers share a place. Use 0 places to list everyone."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "Places"}}</span>
+        <input name="awardsTopN" type="number" min="0" value="3" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="awardsPdf" type="submit">{{T "Awards (PDF)"}}</button>
+      </div>
+
+      <h2>{{T "Archive competition"}}</h2>
+      <p>
+        {{T "Save the competition's routes, final standings, and climbs to a new archive using the archive ID entered above, or list existing archives."}}
+      </p>
+      <div class="input-row">
+        <button name="action" value="archive" type="submit">{{T "Archive"}}</button>
+        <button name="action" value="archives" type="submit">{{T "List archives"}}</button>
+      </div>
+
+      <h2>{{T "Update routes"}}</h2>
+      <p>
+        {{T "Upload new area and route data in CSV format and use it to replace the existing data. Alternatively, upload a single JSON or YAML file containing a list of areas, each with a nested list of routes."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "Areas CSV"}}</span>
         <input name="areas" type="file" accept=".csv" />
       </div>
       <div class="input-row">
-        <span class="label">Routes CSV</span>
+        <span class="label">{{T "Routes CSV"}}</span>
         <input name="routes" type="file" accept=".csv" />
       </div>
+      <div class="input-row">
+        <span class="label">JSON/YAML</span>
+        <input name="data" type="file" accept=".json,.yaml,.yml" />
+      </div>
       <div class="input-row">
         <button name="action" value="routes" type="submit">
-          Update routes
+          {{T "Update routes"}}
         </button>
       </div>
+      <p>
+        {{T "Download the existing area and route data in the same CSV format."}}
+      </p>
+      <div class="input-row">
+        <button name="action" value="areasCsv" type="submit">{{T "Areas (CSV)"}}</button>
+        <button name="action" value="routesCsv" type="submit">{{T "Routes (CSV)"}}</button>
+      </div>
 
-      <h2>Lock or unlock database</h2>
-      <p>Set database to be read-only or writable.</p>
+      <h2>{{T "Check routes against Mountain Project"}}</h2>
+      <p>
+        {{T "Upload a Mountain Project route export in CSV format and compare it against routes with Mountain Project IDs, reporting mismatched names, grades, and heights."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "MP CSV"}}</span>
+        <input name="mpRoutes" type="file" accept=".csv" />
+      </div>
+      <div class="input-row">
+        <input id="mpUpdate" name="mpUpdate" value="1" type="checkbox">
+        <label for="mpUpdate">{{T "Also fill in empty fields"}}</label>
+      </div>
+      <div class="input-row">
+        <button name="action" value="mountainProject" type="submit">
+          {{T "Check routes"}}
+        </button>
+      </div>
+
+      <h2>{{T "Lock or unlock database"}}</h2>
+      <p>{{T "Set database to be read-only or writable."}}</p>
+      <div class="input-row">
+        <button name="action" value="readonly" type="submit">{{T "Read-only"}}</button>
+        <button name="action" value="writable" type="submit">{{T "Writable"}}</button>
+      </div>
+
+      <h2>{{T "Team size"}}</h2>
+      <p>{{T "Set the maximum number of members on a team."}}</p>
+      <div class="input-row">
+        <span class="label">{{T "Size"}}</span>
+        <input name="teamSize" type="number" min="1" value="2" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="teamSize" type="submit">{{T "Set team size"}}</button>
+      </div>
+
+      <h2>{{T "Register participants"}}</h2>
+      <p>
+        {{T "Upload a roster in CSV format with <code>name</code>, <code>email</code>, and optional <code>team</code> and <code>category</code> columns to create accounts, teams, and invite codes. Participants who are already registered are skipped."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "Roster CSV"}}</span>
+        <input name="roster" type="file" accept=".csv" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="roster" type="submit">{{T "Register"}}</button>
+      </div>
+
+      <h2>{{T "Manage teams"}}</h2>
+      <p>
+        {{T "Teams can be identified by team ID or invite code, and users by user ID. List teams to see their IDs, invite codes, and members."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "Team"}}</span>
+        <input name="team" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Other team"}}</span>
+        <input name="otherTeam" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "User"}}</span>
+        <input name="user" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Name"}}</span>
+        <input name="name" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="listTeams" type="submit">{{T "List teams"}}</button>
+        <button name="action" value="renameTeam" type="submit"
+          title="{{T "Rename team to name"}}">{{T "Rename"}}</button>
+        <button name="action" value="mergeTeams" type="submit"
+          title="{{T "Move other team's members to team"}}">{{T "Merge"}}</button>
+        <button name="action" value="splitTeam" type="submit"
+          title="{{T "Move user from team to new team with name"}}">{{T "Split"}}</button>
+        <button name="action" value="moveUser" type="submit"
+          title="{{T "Move user to team"}}">{{T "Move user"}}</button>
+        <button name="action" value="rotateInvite" type="submit"
+          title="{{T "Give team a new invite code"}}">{{T "New invite code"}}</button>
+      </div>
+
+      <h2>{{T "Correct climbs"}}</h2>
+      <p>
+        {{T "View or change a user's recorded climbs. A reason must be supplied for each change; it's saved along with the change. To view, change, or verify climbs that a user reported on a team that they later left, also enter that team."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "User"}}</span>
+        <input name="climbUser" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Old team"}}</span>
+        <input name="climbTeam" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Route"}}</span>
+        <input name="climbRoute" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "State"}}</span>
+        <select name="climbState">
+          <option value="none">{{T "Not climbed"}}</option>
+          <option value="lead">{{T "Lead"}}</option>
+          <option value="tr">{{T "Top-rope"}}</option>
+          <option value="flash">{{T "Flash"}}</option>
+          <option value="top">{{T "Top"}}</option>
+          <option value="zone">{{T "Zone"}}</option>
+        </select>
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Attempts"}}</span>
+        <input name="climbAttempts" type="number" min="1" title="{{T "Number of attempts for a top or zone"}}" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Reason"}}</span>
+        <input name="climbReason" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <button name="action" value="userClimbs" type="submit">{{T "View climbs"}}</button>
+        <button name="action" value="setClimb" type="submit">{{T "Change climb"}}</button>
+      </div>
+
+      <h2>{{T "Verify climbs"}}</h2>
+      <p>
+        {{T "Climbs of routes with the <code>verify</code> column set only count after they're approved. Enter the user and route above to approve or reject a climb."}}
+      </p>
+      <div class="input-row">
+        <button name="action" value="pendingClimbs" type="submit">{{T "List pending"}}</button>
+        <button name="action" value="approveClimb" type="submit">{{T "Approve"}}</button>
+        <button name="action" value="rejectClimb" type="submit">{{T "Reject"}}</button>
+      </div>
+
+      <h2>{{T "Check teams, users, and invites"}}</h2>
+      <p>
+        {{T "Report users, teams, and invite codes that don't reference each other consistently, e.g. users listed on multiple teams or invite codes without teams."}}
+      </p>
+      <div class="input-row">
+        <input id="fsckRepair" name="fsckRepair" value="1" type="checkbox">
+        <label for="fsckRepair">{{T "Also repair problems where possible"}}</label>
+      </div>
+      <div class="input-row">
+        <button name="action" value="fsck" type="submit">{{T "Check"}}</button>
+      </div>
+
+      <h2>{{T "Privacy requests"}}</h2>
+      <p>
+        {{T "Export or erase all data held about a user, identified by user ID or email address. Erasing deletes the user's account, so erase them from any other competitions (by user ID) first. Client log entries can be exported but not erased. Archives don't record user IDs, so erased users' names and climbs remain in them. Teams whose only member is erased are deleted along with their invite codes."}}
+      </p>
+      <div class="input-row">
+        <span class="label">{{T "User"}}</span>
+        <input name="privacyUser" type="text" autocomplete="off" />
+      </div>
+      <div class="input-row">
+        <span class="label">{{T "Confirm"}}</span>
+        <input
+          name="privacyConfirm"
+          type="text"
+          autocomplete="off"
+          placeholder="{{T "Repeat user to erase"}}"
+        />
+      </div>
       <div class="input-row">
-        <button name="action" value="readonly" type="submit">Read-only</button>
-        <button name="action" value="writable" type="submit">Writable</button>
+        <button name="action" value="exportUser" type="submit">{{T "Export"}}</button>
+        <button name="action" value="eraseUser" type="submit">{{T "Erase"}}</button>
       </div>
 
-      <h2>Delete empty teams</h2>
-      <p>Delete all teams that don't have any members.</p>
+      <h2>{{T "Delete empty teams"}}</h2>
+      <p>{{T "Delete all teams that don't have any members."}}</p>
       <div class="input-row">
         <button name="action" value="emptyTeams" type="submit">
-          Delete empty teams
+          {{T "Delete empty teams"}}
         </button>
       </div>
 
-      <h2>Clear scores</h2>
-      <p>Clear scores for all teams and users.</p>
+      <h2>{{T "Clear scores"}}</h2>
+      <p>{{T "Clear scores for all teams and users."}}</p>
       <div class="input-row">
         <input id="deleteTeams" name="deleteTeams" value="1" type="checkbox">
-        <label for="deleteTeams">Also delete all teams</label>
+        <label for="deleteTeams">{{T "Also delete all teams"}}</label>
       </div>
       <div class="input-row">
-        <span class="label">Confirm</span>
+        <span class="label">{{T "Confirm"}}</span>
         <input
           name="confirm"
           type="text"
           autocomplete="off"
-          placeholder="Type 'REALLY CLEAR SCORES'"
+          placeholder="{{T "Type 'REALLY CLEAR SCORES'"}}"
           style="min-width: 15em"
         />
       </div>
       <div class="input-row">
         <button name="action" value="clearScores" type="submit">
-          Clear scores
+          {{T "Clear scores"}}
         </button>
       </div>
     </form>
@@ -0,0 +1,229 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleArchive handles an "archive" POST request.
+// It freezes competition comp's routes, final standings, and per-climber climbs
+// into a new doc in the archive collection named by the "archive" parameter.
+// Teams are written to db.TeamChunk docs under the archive doc to stay under
+// Firestore's document size limit. Existing archives are never overwritten.
+func handleArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	id := r.FormValue("archive")
+	if !validArchiveID(id) {
+		http.Error(w, loc.T("Bad archive ID %q", id), http.StatusBadRequest)
+		return
+	}
+	ref := client.Collection(db.ArchiveCollectionPath).Doc(id)
+	if _, err := ref.Get(ctx); err == nil {
+		http.Error(w, loc.T("Archive %q already exists", id), http.StatusConflict)
+		return
+	} else if status.Code(err) != codes.NotFound {
+		http.Error(w, loc.T("Failed getting %v: %v", ref.Path, err), http.StatusInternalServerError)
+		return
+	}
+
+	sd, err := getScores(ctx, client, comp)
+	if err != nil {
+		http.Error(w, loc.T("Failed loading scores: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Use a unique prefix for the chunks so that a concurrent request archiving
+	// to the same ID can't overwrite them.
+	now := time.Now()
+	arch := db.Archive{
+		Competition: comp,
+		Time:        now,
+		SortedData:  sd.sorted,
+		NumTeams:    len(sd.teams),
+		ChunkPrefix: fmt.Sprintf("%d-", now.UnixNano()),
+	}
+	coll := client.Collection(db.ArchiveTeamCollectionPath(id))
+	log.Printf("Archiving %d team(s) from competition %q to %v", len(sd.teams), comp, ref.Path)
+	if arch.NumChunks, err = writeTeamChunks(ctx, client, coll, arch.ChunkPrefix, newArchivedTeams(sd.teams, false)); err != nil {
+		http.Error(w, loc.T("Failed writing teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, err := ref.Create(ctx, arch); err != nil {
+		if err := deleteTeamChunks(ctx, client, coll, arch.ChunkPrefix, arch.NumChunks); err != nil {
+			log.Printf("Failed deleting unused chunks from %v: %v", coll.Path, err)
+		}
+		if status.Code(err) == codes.AlreadyExists {
+			http.Error(w, loc.T("Archive %q already exists", id), http.StatusConflict)
+		} else {
+			http.Error(w, loc.T("Failed writing %v: %v", ref.Path, err), http.StatusInternalServerError)
+		}
+		return
+	}
+	fmt.Fprintln(w, loc.T("Archived %d team(s) to %q", len(sd.teams), id))
+}
+
+// handleListArchives handles an "archives" POST request.
+// It writes a list of all archived competitions.
+func handleListArchives(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var lines []string
+	it := client.Collection(db.ArchiveCollectionPath).Documents(ctx)
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			http.Error(w, loc.T("Failed getting archive: %v", err), http.StatusInternalServerError)
+			return
+		}
+		var arch db.Archive
+		if err := snap.DataTo(&arch); err != nil {
+			http.Error(w, loc.T("Failed decoding %v: %v", snap.Ref.Path, err), http.StatusInternalServerError)
+			return
+		}
+		lines = append(lines, loc.T("%q: competition %q archived %v with %d team(s)",
+			snap.Ref.ID, arch.Competition, arch.Time.Format(time.RFC3339), arch.NumTeams))
+	}
+	sort.Strings(lines)
+
+	fmt.Fprintln(w, loc.T("%d archive(s)", len(lines)))
+	for _, ln := range lines {
+		fmt.Fprintln(w, ln)
+	}
+}
+
+// errBadArchiveID is returned by getArchive if the requested archive ID is invalid.
+var errBadArchiveID = errors.New("bad archive ID")
+
+// validArchiveID returns true if id can be used as the ID of an archive doc.
+func validArchiveID(id string) bool {
+	return id != "" && db.ValidCompetitionID(id)
+}
+
+// getArchive returns the archive with the supplied ID.
+// errBadArchiveID is returned if id is invalid.
+func getArchive(ctx context.Context, client *firestore.Client, id string) (*db.Archive, error) {
+	if !validArchiveID(id) {
+		return nil, errBadArchiveID
+	}
+	var arch db.Archive
+	if err := db.GetDoc(ctx, client.Collection(db.ArchiveCollectionPath).Doc(id), &arch); err != nil {
+		return nil, fmt.Errorf("failed getting archive %q: %v", id, err)
+	}
+	return &arch, nil
+}
+
+// loadErrorStatus returns the HTTP status code to use when reporting err,
+// which was returned by loadScores or loadSortedData.
+func loadErrorStatus(err error) int {
+	if err == errBadArchiveID {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// loadScores returns summarized scores for r and the time at which they were computed.
+// If the "archive" parameter is set, scores are read from the named archive. Otherwise,
+// competition comp's current standings are returned as described in getStandings.
+func loadScores(ctx context.Context, r *http.Request, client *firestore.Client, comp string) (
+	[]teamSummary, []userSummary, time.Time, error) {
+	id := r.FormValue("archive")
+	if id == "" {
+		return getStandings(ctx, client, comp, maxStandingsAge)
+	}
+
+	arch, err := getArchive(ctx, client, id)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	ats, err := readTeamChunks(ctx, client, client.Collection(db.ArchiveTeamCollectionPath(id)),
+		arch.ChunkPrefix, arch.NumChunks)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("failed getting archive %q teams: %v", id, err)
+	}
+	teams, users := getArchivedScores(ats, arch.SortedData.Areas)
+	return teams, users, arch.Time, nil
+}
+
+// newArchivedTeams converts teams (as returned by getScores) to db.ArchivedTeam structs.
+// User IDs are only included if uids is true: archives omit them so that erased users
+// can't be linked to their archived climbs.
+func newArchivedTeams(teams []teamSummary, uids bool) []db.ArchivedTeam {
+	ats := make([]db.ArchivedTeam, 0, len(teams))
+	for _, ts := range teams {
+		at := db.ArchivedTeam{
+			Name:      ts.Name,
+			Score:     ts.Score,
+			NumClimbs: ts.NumClimbs,
+			Height:    ts.Height,
+			Attempts:  ts.Attempts,
+			Users:     make([]db.ArchivedUser, 0, len(ts.Users)),
+		}
+		for _, us := range ts.Users {
+			au := db.ArchivedUser{
+				Name:      us.Name,
+				Score:     us.Score,
+				NumClimbs: us.NumClimbs,
+				Height:    us.Height,
+				Attempts:  us.Attempts,
+				Climbs:    us.Climbs,
+			}
+			if uids {
+				au.UID = us.UID
+			}
+			at.Users = append(at.Users, au)
+		}
+		ats = append(ats, at)
+	}
+	return ats
+}
+
+// getArchivedScores returns the frozen standings from ats in the same form as getScores.
+// areas is used to describe users' climbs.
+func getArchivedScores(ats []db.ArchivedTeam, areas []db.Area) ([]teamSummary, []userSummary) {
+	var teams []teamSummary
+	var users []userSummary
+	for _, at := range ats {
+		ts := teamSummary{
+			Name:      at.Name,
+			Score:     at.Score,
+			NumClimbs: at.NumClimbs,
+			Height:    at.Height,
+			Attempts:  at.Attempts,
+		}
+		for _, au := range at.Users {
+			us := userSummary{
+				UID:        au.UID,
+				Name:       au.Name,
+				Team:       at.Name,
+				Score:      au.Score,
+				NumClimbs:  au.NumClimbs,
+				Height:     au.Height,
+				Attempts:   au.Attempts,
+				ClimbsDesc: makeClimbsDesc(au.Climbs, areas),
+				Climbs:     au.Climbs,
+			}
+			ts.Users = append(ts.Users, us)
+			users = append(users, us)
+		}
+		teams = append(teams, ts)
+	}
+	sortUsers(users)
+	return teams, users
+}
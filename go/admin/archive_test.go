@@ -0,0 +1,154 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	sorted := db.SortedData{Areas: []db.Area{{ID: "a1", Name: "A1", Routes: []db.Route{
+		{ID: "r1", Name: "R1", Lead: 10, TR: 5, Height: 60},
+		{ID: "r2", Name: "R2", Lead: 6, TR: 3, Height: 30},
+	}}}}
+	u1 := userSummary{Name: "User 1", Team: "Team A", Score: 16, NumClimbs: 2, Height: 90,
+		ClimbsDesc: "R1 (L)\nR2 (L)", Climbs: map[string]db.ClimbState{"r1": db.Lead, "r2": db.Lead}}
+	u2 := userSummary{Name: "User 2", Team: "Team A", Score: 5, NumClimbs: 1, Height: 60,
+		ClimbsDesc: "R1 (TR)", Climbs: map[string]db.ClimbState{"r1": db.TopRope}}
+	u3 := userSummary{Name: "User 3", Team: "Team B", Score: 6, NumClimbs: 1, Height: 30, Attempts: 2,
+		ClimbsDesc: "R2 (L)", Climbs: map[string]db.ClimbState{"r2": db.Lead}}
+	teams := []teamSummary{
+		{"Team A", 21, 3, 150, 0, []userSummary{u1, u2}},
+		{"Team B", 6, 1, 30, 2, []userSummary{u3}},
+	}
+
+	gotTeams, gotUsers := getArchivedScores(newArchivedTeams(teams, false), sorted.Areas)
+	if !reflect.DeepEqual(gotTeams, teams) {
+		t.Errorf("getArchivedScores returned teams %+v; want %+v", gotTeams, teams)
+	}
+	if want := []userSummary{u1, u3, u2}; !reflect.DeepEqual(gotUsers, want) {
+		t.Errorf("getArchivedScores returned users %+v; want %+v", gotUsers, want)
+	}
+
+	// User IDs should be preserved if requested.
+	teams[0].Users[0].UID = "u1"
+	u1.UID = "u1"
+	gotTeams, gotUsers = getArchivedScores(newArchivedTeams(teams, true), sorted.Areas)
+	if !reflect.DeepEqual(gotTeams, teams) {
+		t.Errorf("getArchivedScores with UIDs returned teams %+v; want %+v", gotTeams, teams)
+	}
+	if want := []userSummary{u1, u3, u2}; !reflect.DeepEqual(gotUsers, want) {
+		t.Errorf("getArchivedScores with UIDs returned users %+v; want %+v", gotUsers, want)
+	}
+}
+
+func TestChunkTeams(t *testing.T) {
+	// Each team's JSON encoding is a bit larger than a quarter of maxChunkBytes.
+	var ats []db.ArchivedTeam
+	for i := 0; i < 10; i++ {
+		ats = append(ats, db.ArchivedTeam{Name: fmt.Sprintf("Team %d", i),
+			Users: []db.ArchivedUser{{Name: strings.Repeat("x", maxChunkBytes/4)}}})
+	}
+	chunks, err := chunkTeams(ats)
+	if err != nil {
+		t.Fatal("chunkTeams failed: ", err)
+	}
+	var got []db.ArchivedTeam
+	for i, c := range chunks {
+		if len(c) != 3 && i != len(chunks)-1 {
+			t.Errorf("Chunk %d has %d team(s); want 3", i, len(c))
+		}
+		got = append(got, c...)
+	}
+	if len(chunks) != 4 || !reflect.DeepEqual(got, ats) {
+		t.Errorf("chunkTeams returned %d chunk(s) with different teams", len(chunks))
+	}
+
+	big := db.ArchivedTeam{Name: "Big", Users: []db.ArchivedUser{{Name: strings.Repeat("x", maxChunkBytes)}}}
+	if _, err := chunkTeams([]db.ArchivedTeam{big}); err == nil {
+		t.Error("chunkTeams unexpectedly accepted oversized team")
+	}
+}
+
+func TestHandleArchive(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	// Use enough teams to require multiple chunks.
+	const numTeams = 1000
+	addFakeCompetition(ff, numTeams, 100, 0)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	ctx := context.Background()
+	post := func(handler func(context.Context, http.ResponseWriter, *http.Request, *firestore.Client, string),
+		params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(ctx, w, req, client, db.DefaultCompetition)
+		return w
+	}
+
+	if w := post(handleArchive, url.Values{"archive": {"test"}}); w.Code != 200 {
+		t.Fatalf("handleArchive returned %d: %v", w.Code, w.Body.String())
+	}
+	if n := ff.count("archives/test/teams"); n < 2 {
+		t.Errorf("Archive has %d chunk(s); want at least 2", n)
+	}
+	if w := post(handleArchive, url.Values{"archive": {"test"}}); w.Code != 409 {
+		t.Errorf("handleArchive with existing ID returned %d; want 409", w.Code)
+	}
+	if w := post(handleListArchives, nil); !strings.Contains(w.Body.String(), fmt.Sprintf("with %d team(s)", numTeams)) {
+		t.Errorf("handleListArchives wrote unexpected output:\n%s", w.Body.String())
+	}
+
+	sd, err := getScores(ctx, client, db.DefaultCompetition)
+	if err != nil {
+		t.Fatal("getScores failed: ", err)
+	}
+	wantTeams, wantUsers := sd.teams, sd.users
+	// Archives don't include user IDs.
+	for i := range wantTeams {
+		for j := range wantTeams[i].Users {
+			wantTeams[i].Users[j].UID = ""
+		}
+	}
+	for i := range wantUsers {
+		wantUsers[i].UID = ""
+	}
+	req := httptest.NewRequest("GET", "/?archive=test", nil)
+	gotTeams, gotUsers, _, err := loadScores(ctx, req, client, db.DefaultCompetition)
+	if err != nil {
+		t.Fatal("loadScores failed: ", err)
+	}
+	if !reflect.DeepEqual(gotTeams, wantTeams) {
+		t.Error("loadScores returned different teams than getScores")
+	}
+	if !reflect.DeepEqual(gotUsers, wantUsers) {
+		t.Error("loadScores returned different users than getScores")
+	}
+
+	for _, id := range []string{"../teams", "a/b"} {
+		if w := post(handlePostScoresTeams, url.Values{"archive": {id}}); w.Code != 400 {
+			t.Errorf("handlePostScoresTeams with archive %q returned %d; want 400", id, w.Code)
+		}
+		if w := post(handleScoresXLSX, url.Values{"archive": {id}}); w.Code != 400 {
+			t.Errorf("handleScoresXLSX with archive %q returned %d; want 400", id, w.Code)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/go-pdf/fpdf"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+const (
+	defaultAwardsTopN = 3               // default number of places in each award category
+	uncategorized     = "Uncategorized" // heading for users without categories
+	allClimbers       = "Climbers"      // heading used if no users have categories
+)
+
+// handleAwardsPDF handles an "awardsPdf" POST request.
+// It writes a PDF containing the top teams and the top climbers in each user category
+// (see db.User.Category), followed by a certificate for each of the listed climbers.
+// The "awardsTopN" parameter specifies the number of places to list (0 for all).
+func handleAwardsPDF(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	topN := defaultAwardsTopN
+	if s := r.FormValue("awardsTopN"); s != "" {
+		var err error
+		if topN, err = strconv.Atoi(s); err != nil || topN < 0 {
+			http.Error(w, loc.T("Bad number of places %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	// Categories are only available for users in the current competition.
+	if r.FormValue("archive") != "" {
+		http.Error(w, loc.T("Awards don't support archives"), http.StatusBadRequest)
+		return
+	}
+
+	sd, err := getScores(ctx, client, comp)
+	if err != nil {
+		http.Error(w, loc.T("Failed loading scores: %v", err), http.StatusInternalServerError)
+		return
+	}
+	cats := make(map[string]string) // keyed by user ID
+	if err := loadCollection(ctx, client.Collection(db.UserCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var u db.User
+		err := snap.DataTo(&u)
+		cats[snap.Ref.ID] = u.Category
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading users: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	teams := sd.teams
+	sortTeams(teams)
+	aw := awards{
+		teams: topRanked(len(teams), func(i int) bool {
+			return teams[i].Score == teams[i-1].Score && teams[i].Attempts == teams[i-1].Attempts
+		}, topN),
+		categories: groupByCategory(sd.users, cats, topN),
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=awards.pdf")
+	if err := writeAwardsPDF(w, &aw, teams, loc); err != nil {
+		http.Error(w, loc.T("Failed writing PDF: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// awards describes the places to list in a PDF written by writeAwardsPDF.
+type awards struct {
+	teams      []rankedIndex // indexes into teams sorted by descending score
+	categories []awardCategory
+}
+
+// rankedIndex is an index into a slice of teams or users along with its 1-indexed rank.
+type rankedIndex struct {
+	index, rank int
+}
+
+// awardCategory contains the ranked climbers in a user category.
+type awardCategory struct {
+	name  string
+	users []userSummary
+	ranks []int // parallel to users
+}
+
+// topRanked returns ranks for the first n items, which must be sorted by rank.
+// tied reports whether item i (greater than 0) is tied with item i-1. Tied items share
+// the same rank, e.g. 1, 2, 2, 4. Only items with ranks less than or equal to topN are
+// returned unless topN is 0.
+func topRanked(n int, tied func(i int) bool, topN int) []rankedIndex {
+	var ranked []rankedIndex
+	for i := 0; i < n; i++ {
+		rank := i + 1
+		if i > 0 && tied(i) {
+			rank = ranked[i-1].rank
+		}
+		if topN > 0 && rank > topN {
+			break
+		}
+		ranked = append(ranked, rankedIndex{i, rank})
+	}
+	return ranked
+}
+
+// groupByCategory splits users (sorted as by sortUsers) into categories using
+// cats, which maps from user ID to category name. Categories are sorted by name,
+// with users without categories listed last. The top topN places in each category
+// are kept as described in topRanked.
+func groupByCategory(users []userSummary, cats map[string]string, topN int) []awardCategory {
+	grouped := make(map[string][]userSummary)
+	var names []string
+	for _, u := range users {
+		name := strings.TrimSpace(cats[u.UID])
+		if _, ok := grouped[name]; !ok {
+			names = append(names, name)
+		}
+		grouped[name] = append(grouped[name], u)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "") != (names[j] == "") {
+			return names[j] == ""
+		}
+		return names[i] < names[j]
+	})
+
+	var res []awardCategory
+	for _, name := range names {
+		us := grouped[name]
+		ac := awardCategory{name: name}
+		if name == "" {
+			ac.name = uncategorized
+			if len(names) == 1 {
+				ac.name = allClimbers
+			}
+		}
+		tied := func(i int) bool { return us[i].Score == us[i-1].Score && us[i].Attempts == us[i-1].Attempts }
+		for _, ri := range topRanked(len(us), tied, topN) {
+			ac.users = append(ac.users, us[ri.index])
+			ac.ranks = append(ac.ranks, ri.rank)
+		}
+		res = append(res, ac)
+	}
+	return res
+}
+
+// ordinal returns n as an English ordinal number, e.g. "1st" or "12th".
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// writeAwardsPDF writes a PDF document describing aw to w, localized using loc.
+// teams should be sorted by descending score.
+func writeAwardsPDF(w io.Writer, aw *awards, teams []teamSummary, loc *localizer) error {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	pdf.SetTitle(loc.T("Awards"), true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // UTF-8 to cp1252 for core fonts
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+
+	// writeTable writes a table with the supplied column headings and relative widths.
+	writeTable := func(heads []string, widths []float64, rows [][]string) {
+		var total float64
+		for _, w := range widths {
+			total += w
+		}
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(221, 221, 221)
+		for i, h := range heads {
+			pdf.CellFormat(contentW*widths[i]/total, 7, tr(h), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 10)
+		for _, row := range rows {
+			for i, val := range row {
+				align := "L"
+				if i == 0 || i >= len(row)-3 {
+					align = "R" // rank and numeric columns
+				}
+				pdf.CellFormat(contentW*widths[i]/total, 6, tr(val), "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		pdf.Ln(6)
+	}
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW, 10, tr(loc.T("Final standings")), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	if len(aw.teams) > 0 {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(contentW, 8, tr(loc.T("Teams")), "", 1, "L", false, 0, "")
+		var rows [][]string
+		for _, ri := range aw.teams {
+			ts := teams[ri.index]
+			var names []string
+			for _, u := range ts.Users {
+				names = append(names, u.Name)
+			}
+			rows = append(rows, []string{loc.ordinal(ri.rank), ts.Name, strings.Join(names, ", "),
+				strconv.Itoa(ts.Score), strconv.Itoa(ts.NumClimbs), loc.height(ts.Height)})
+		}
+		writeTable([]string{loc.T("Place"), loc.T("Team"), loc.T("Climbers"), loc.T("Score"),
+			loc.T("Climbs"), loc.T("Height")},
+			[]float64{1.2, 3, 5, 1.2, 1.2, 1.2}, rows)
+	}
+
+	// catName returns ac's name, translating it if it was generated by groupByCategory.
+	catName := func(ac awardCategory) string {
+		if ac.name == uncategorized || ac.name == allClimbers {
+			return loc.T(ac.name)
+		}
+		return ac.name
+	}
+
+	for _, ac := range aw.categories {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(contentW, 8, tr(catName(ac)), "", 1, "L", false, 0, "")
+		var rows [][]string
+		for i, u := range ac.users {
+			rows = append(rows, []string{loc.ordinal(ac.ranks[i]), u.Name, u.Team,
+				strconv.Itoa(u.Score), strconv.Itoa(u.NumClimbs), loc.height(u.Height)})
+		}
+		writeTable([]string{loc.T("Place"), loc.T("Climber"), loc.T("Team"), loc.T("Score"),
+			loc.T("Climbs"), loc.T("Height")},
+			[]float64{1.2, 4, 4, 1.2, 1.2, 1.2}, rows)
+	}
+
+	// Write a certificate for each listed climber.
+	for _, ac := range aw.categories {
+		for i, u := range ac.users {
+			pdf.AddPage()
+			pageW, pageH := pdf.GetPageSize()
+			pdf.SetLineWidth(1.5)
+			pdf.Rect(left, left, pageW-2*left, pageH-2*left, "D")
+			pdf.SetLineWidth(0.2)
+
+			center := func(style string, size float64, text string) {
+				pdf.SetFont("Helvetica", style, size)
+				pdf.CellFormat(contentW, size*0.6, tr(text), "", 1, "C", false, 0, "")
+			}
+			pdf.SetY(pageH / 4)
+			center("B", 32, loc.T("Certificate of Achievement"))
+			pdf.Ln(14)
+			center("", 16, loc.T("Awarded to"))
+			pdf.Ln(6)
+			center("B", 28, u.Name)
+			pdf.Ln(10)
+			center("B", 22, loc.T("%s place", loc.ordinal(ac.ranks[i])))
+			pdf.Ln(2)
+			center("", 16, catName(ac))
+			pdf.Ln(14)
+			center("", 14, loc.T("Score: %d", u.Score))
+			pdf.Ln(2)
+			center("", 14, loc.T("Climbs: %d (%s)", u.NumClimbs, loc.height(u.Height)))
+			if u.Team != "" {
+				pdf.Ln(2)
+				center("", 14, loc.T("Team: %s", u.Team))
+			}
+		}
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTopRanked(t *testing.T) {
+	scores := []int{50, 40, 40, 30, 20, 20}
+	for _, tc := range []struct {
+		topN int
+		want []rankedIndex
+	}{
+		{0, []rankedIndex{{0, 1}, {1, 2}, {2, 2}, {3, 4}, {4, 5}, {5, 5}}},
+		{1, []rankedIndex{{0, 1}}},
+		{2, []rankedIndex{{0, 1}, {1, 2}, {2, 2}}},
+		{3, []rankedIndex{{0, 1}, {1, 2}, {2, 2}}},
+		{5, []rankedIndex{{0, 1}, {1, 2}, {2, 2}, {3, 4}, {4, 5}, {5, 5}}},
+	} {
+		if got := topRanked(len(scores), func(i int) bool { return scores[i] == scores[i-1] }, tc.topN); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("topRanked(..., %d) = %v; want %v", tc.topN, got, tc.want)
+		}
+	}
+}
+
+func TestGroupByCategory(t *testing.T) {
+	users := []userSummary{
+		{UID: "u1", Name: "User 1", Score: 50},
+		{UID: "u2", Name: "User 2", Score: 40},
+		{UID: "u3", Name: "User 3", Score: 30},
+		{UID: "u4", Name: "User 4", Score: 30},
+		{UID: "u5", Name: "User 5", Score: 20},
+		{UID: "u6", Name: "User 6", Score: 20, Attempts: 4}, // more attempts than u5, so not tied
+	}
+	cats := map[string]string{"u1": "Open", "u2": "Youth", "u3": "Open", "u4": "Open"}
+	got := groupByCategory(users, cats, 2)
+	want := []awardCategory{
+		{"Open", []userSummary{users[0], users[2], users[3]}, []int{1, 2, 2}},
+		{"Youth", []userSummary{users[1]}, []int{1}},
+		{uncategorized, []userSummary{users[4], users[5]}, []int{1, 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByCategory returned %+v; want %+v", got, want)
+	}
+
+	// If no users have categories, a generic name should be used.
+	if got := groupByCategory(users[:1], nil, 0); len(got) != 1 || got[0].name != allClimbers {
+		t.Errorf("groupByCategory without categories returned %+v", got)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th",
+		21: "21st", 22: "22nd", 101: "101st", 111: "111th",
+	} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q; want %q", n, got, want)
+		}
+	}
+}
+
+func TestWriteAwardsPDF(t *testing.T) {
+	u1 := userSummary{UID: "u1", Name: "José Pérez", Team: "Team A", Score: 50, NumClimbs: 3, Height: 150}
+	u2 := userSummary{UID: "u2", Name: "User 2", Team: "Team A", Score: 40, NumClimbs: 2, Height: 100}
+	teams := []teamSummary{{"Team A", 90, 5, 250, 0, []userSummary{u1, u2}}}
+	aw := awards{
+		teams:      []rankedIndex{{0, 1}},
+		categories: []awardCategory{{"Open", []userSummary{u1, u2}, []int{1, 2}}},
+	}
+	for _, loc := range []*localizer{defaultLocalizer, {lang: "es", metres: true}} {
+		var b bytes.Buffer
+		if err := writeAwardsPDF(&b, &aw, teams, loc); err != nil {
+			t.Fatalf("writeAwardsPDF with %+v failed: %v", *loc, err)
+		}
+		if !bytes.HasPrefix(b.Bytes(), []byte("%PDF-")) {
+			t.Errorf("writeAwardsPDF with %+v wrote non-PDF data starting with %q", *loc, b.Bytes()[:10])
+		}
+		// One standings page plus one certificate per climber.
+		if got := bytes.Count(b.Bytes(), []byte("/Type /Page\n")); got != 3 {
+			t.Errorf("writeAwardsPDF with %+v wrote %d page(s); want 3", *loc, got)
+		}
+	}
+}
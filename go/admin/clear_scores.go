@@ -19,29 +19,30 @@ import (
 // handleClearScores handles an "clearScores" POST request.
 // It clears all scores from Cloud Firestore.
 // If the "deleteTeams" parameter is set to "1", all teams and invite codes are also deleted.
-func handleClearScores(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
+func handleClearScores(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
 	if r.FormValue("confirm") != "REALLY CLEAR SCORES" {
-		http.Error(w, "Didn't confirm that we really want to clear scores", http.StatusBadRequest)
+		http.Error(w, loc.T("Didn't confirm that we really want to clear scores"), http.StatusBadRequest)
 		return
 	}
 
 	deleteTeams := r.FormValue("deleteTeams") == "1"
 
 	// First, iterate over team documents.
-	it := client.Collection(db.TeamCollectionPath).DocumentRefs(ctx)
+	it := client.Collection(db.TeamCollectionPath(comp)).DocumentRefs(ctx)
 	for {
 		ref, err := it.Next()
 		if err == iterator.Done {
 			break
 		} else if err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting team ref: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed getting team ref: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		if deleteTeams {
 			log.Printf("Deleting team doc %s", ref.Path)
 			if _, err := ref.Delete(ctx); err != nil {
-				http.Error(w, fmt.Sprintf("Failed deleting team doc %v: %v", ref.Path, err),
+				http.Error(w, loc.T("Failed deleting team doc %v: %v", ref.Path, err),
 					http.StatusInternalServerError)
 				return
 			}
@@ -50,7 +51,7 @@ func handleClearScores(ctx context.Context, w http.ResponseWriter, r *http.Reque
 
 		var team db.Team
 		if err := db.GetDoc(ctx, ref, &team); err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting team doc: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed getting team doc: %v", err), http.StatusInternalServerError)
 			return
 		}
 
@@ -65,25 +66,25 @@ func handleClearScores(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		if len(updates) > 0 {
 			log.Printf("Clearing scores from team doc %s (%+v)", ref.Path, team)
 			if _, err := ref.Update(ctx, updates); err != nil {
-				http.Error(w, fmt.Sprintf("Failed updating team: %v", err), http.StatusInternalServerError)
+				http.Error(w, loc.T("Failed updating team: %v", err), http.StatusInternalServerError)
 				return
 			}
 		}
 	}
 
 	// Next, iterate over user documents.
-	it = client.Collection(db.UserCollectionPath).DocumentRefs(ctx)
+	it = client.Collection(db.UserCollectionPath(comp)).DocumentRefs(ctx)
 	for {
 		ref, err := it.Next()
 		if err == iterator.Done {
 			break
 		} else if err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting user ref: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed getting user ref: %v", err), http.StatusInternalServerError)
 			return
 		}
 		var user db.User
 		if err := db.GetDoc(ctx, ref, &user); err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting user doc: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed getting user doc: %v", err), http.StatusInternalServerError)
 			return
 		}
 
@@ -93,33 +94,57 @@ func handleClearScores(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		}
 		log.Printf("Updating user doc %s (%+v)", ref.Path, user)
 		if _, err := ref.Update(ctx, updates); err != nil {
-			http.Error(w, fmt.Sprintf("Failed updating user: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed updating user: %v", err), http.StatusInternalServerError)
 			return
 		}
 	}
 
 	if deleteTeams {
-		it := client.Collection(db.InviteCollectionPath).DocumentRefs(ctx)
+		it := client.Collection(db.InviteCollectionPath(comp)).DocumentRefs(ctx)
 		for {
 			ref, err := it.Next()
 			if err == iterator.Done {
 				break
 			} else if err != nil {
-				http.Error(w, fmt.Sprintf("Failed getting invite ref: %v", err), http.StatusInternalServerError)
+				http.Error(w, loc.T("Failed getting invite ref: %v", err), http.StatusInternalServerError)
 				return
 			}
 			log.Printf("Deleting invite doc %s", ref.Path)
 			if _, err := ref.Delete(ctx); err != nil {
-				http.Error(w, fmt.Sprintf("Failed deleting invite doc %v: %v", ref.Path, err),
+				http.Error(w, loc.T("Failed deleting invite doc %v: %v", ref.Path, err),
 					http.StatusInternalServerError)
 				return
 			}
 		}
 	}
 
+	// Make the next scoreboard request recompute the standings.
+	standingsRef := client.Doc(db.StandingsDocPath(comp))
+	log.Printf("Deleting standings doc %s", standingsRef.Path)
+	if _, err := standingsRef.Delete(ctx); err != nil {
+		http.Error(w, loc.T("Failed deleting standings doc: %v", err), http.StatusInternalServerError)
+		return
+	}
+	it = client.Collection(db.StandingsTeamCollectionPath(comp)).DocumentRefs(ctx)
+	for {
+		ref, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			http.Error(w, loc.T("Failed getting standings chunk ref: %v", err), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Deleting standings chunk doc %s", ref.Path)
+		if _, err := ref.Delete(ctx); err != nil {
+			http.Error(w, loc.T("Failed deleting standings chunk doc %v: %v", ref.Path, err),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+
 	if deleteTeams {
-		fmt.Fprintln(w, "Cleared all scores and teams")
+		fmt.Fprintln(w, loc.T("Cleared all scores and teams"))
 	} else {
-		fmt.Fprintln(w, "Cleared all scores")
+		fmt.Fprintln(w, loc.T("Cleared all scores"))
 	}
 }
@@ -0,0 +1,55 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// maxConfigurableTeamSize is the largest team size that can be configured.
+const maxConfigurableTeamSize = 20
+
+// handleTeamSize handles a "teamSize" POST request.
+// It updates the config's maximum number of members per team to the "teamSize" parameter.
+func handleTeamSize(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	size, err := strconv.Atoi(r.FormValue("teamSize"))
+	if err != nil || size < 1 || size > maxConfigurableTeamSize {
+		http.Error(w, loc.T("Team size must be between 1 and %d", maxConfigurableTeamSize), http.StatusBadRequest)
+		return
+	}
+	if _, err := client.Doc(db.ConfigDocPath(comp)).Set(ctx, map[string]interface{}{
+		"teamSize": size,
+	}, firestore.MergeAll); err != nil {
+		http.Error(w, loc.T("Failed setting team size: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, loc.T("Set team size to %d", size))
+}
+
+// getConfig returns competition comp's config.
+// A zero-valued config is returned if the doc doesn't exist.
+func getConfig(ctx context.Context, client *firestore.Client, comp string) (db.Config, error) {
+	var cfg db.Config
+	snap, err := client.Doc(db.ConfigDocPath(comp)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, fmt.Errorf("failed getting config: %v", err)
+	}
+	if err := snap.DataTo(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed decoding config: %v", err)
+	}
+	return cfg, nil
+}
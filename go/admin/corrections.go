@@ -0,0 +1,322 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleUserClimbs handles a "userClimbs" POST request.
+// It writes the climbs recorded for the user named by the "climbUser" parameter,
+// followed by any corrections that judges have made to them. If the "climbTeam"
+// parameter is supplied, the user's climbs in that team (which they may have left)
+// are written instead of their current climbs.
+func handleUserClimbs(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	uid := r.FormValue("climbUser")
+	cloc, err := findClimbs(ctx, client, comp, uid, strings.TrimSpace(r.FormValue("climbTeam")), loc)
+	if err != nil {
+		writeClimbsError(w, loc, err)
+		return
+	}
+	left, err := findLeftClimbs(ctx, client, comp, uid)
+	if err != nil {
+		writeClimbsError(w, loc, err)
+		return
+	}
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		http.Error(w, loc.T("Failed getting sorted data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	corrs, err := getCorrections(ctx, client, comp, uid)
+	if err != nil {
+		http.Error(w, loc.T("Failed getting corrections: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintln(w, loc.T("%d climb(s) for %v (%q) in %v", len(cloc.climbs), uid, cloc.name, cloc.ref.Path))
+	for _, ln := range describeClimbs(cloc.climbs, cloc.attempts, sorted.Areas, loc) {
+		fmt.Fprintln(w, ln)
+	}
+	for _, l := range left {
+		if l.ref.Path != cloc.ref.Path && len(l.climbs) > 0 {
+			fmt.Fprintln(w, loc.T("%d climb(s) in team %v, which the user left", len(l.climbs), l.team))
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, loc.T("%d correction(s)", len(corrs)))
+	for _, c := range corrs {
+		fmt.Fprintf(w, "%v %v: %v -> %v (%q)\n", c.Time.Format(time.RFC3339), c.Route, c.Old, c.New, c.Reason)
+	}
+}
+
+// handleSetClimb handles a "setClimb" POST request.
+// It sets the state of the route named by the "climbRoute" parameter to "climbState"
+// (e.g. "lead", "tr", or "none") for the user named by the "climbUser" parameter.
+// For "top" and "zone", the number of attempts can be supplied via "climbAttempts".
+// The "climbTeam" parameter is handled as in handleUserClimbs.
+// A non-empty "climbReason" parameter must be supplied; it's recorded in a new
+// doc in the corrections collection.
+func handleSetClimb(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	uid := r.FormValue("climbUser")
+	rid := r.FormValue("climbRoute")
+	state, ok := db.ParseClimbState(r.FormValue("climbState"))
+	if !ok {
+		http.Error(w, loc.T("Bad climb state %q", r.FormValue("climbState")), http.StatusBadRequest)
+		return
+	}
+	var attempts int
+	if v := strings.TrimSpace(r.FormValue("climbAttempts")); v != "" {
+		var err error
+		if attempts, err = strconv.Atoi(v); err != nil || attempts < 1 {
+			http.Error(w, loc.T("Bad number of attempts %q", v), http.StatusBadRequest)
+			return
+		}
+		if state != db.Top && state != db.Zone {
+			http.Error(w, loc.T("Attempts can only be recorded for tops and zones"), http.StatusBadRequest)
+			return
+		}
+	}
+	reason := strings.TrimSpace(r.FormValue("climbReason"))
+	if reason == "" {
+		http.Error(w, loc.T("Reason not supplied"), http.StatusBadRequest)
+		return
+	}
+
+	var indexed db.IndexedData
+	if err := db.GetDoc(ctx, client.Doc(db.IndexedDataDocPath(comp)), &indexed); err != nil {
+		http.Error(w, loc.T("Failed getting indexed data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, ok := indexed.Routes[rid]; !ok {
+		http.Error(w, loc.T("Route %q not found", rid), http.StatusBadRequest)
+		return
+	}
+
+	cloc, err := findClimbs(ctx, client, comp, uid, strings.TrimSpace(r.FormValue("climbTeam")), loc)
+	if err != nil {
+		writeClimbsError(w, loc, err)
+		return
+	}
+	old := cloc.climbs[rid]
+	if old == state && (attempts == 0 || attempts == cloc.attempts[rid]) {
+		http.Error(w, loc.T("Route %q is already %v", rid, state), http.StatusBadRequest)
+		return
+	}
+
+	var val interface{} = state
+	if state == db.NotClimbed {
+		val = firestore.Delete
+	}
+	updates := []firestore.Update{{FieldPath: append(cloc.path, rid), Value: val}}
+	if attempts > 0 {
+		updates = append(updates, firestore.Update{FieldPath: cloc.attemptsPath(rid), Value: attempts})
+	} else if _, ok := cloc.attempts[rid]; ok && state != db.Top && state != db.Zone {
+		// Drop attempts that no longer apply to the route's state.
+		updates = append(updates, firestore.Update{FieldPath: cloc.attemptsPath(rid), Value: firestore.Delete})
+	}
+	batch := client.Batch()
+	batch.Update(cloc.ref, updates)
+	batch.Create(client.Collection(db.CorrectionCollectionPath(comp)).NewDoc(), db.Correction{
+		Time:   time.Now(),
+		User:   uid,
+		Team:   cloc.team,
+		Route:  rid,
+		Old:    old,
+		New:    state,
+		Reason: reason,
+	})
+	log.Printf("Changing %v's %v climb from %v to %v in %v: %q", uid, rid, old, state, cloc.ref.Path, reason)
+	if _, err := batch.Commit(ctx); err != nil {
+		http.Error(w, loc.T("Failed updating climb: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := invalidateStandings(ctx, client, comp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Changed %v (%q) climb of %v from %v to %v", uid, cloc.name, rid, old, state))
+	if attempts > 0 {
+		fmt.Fprintln(w, loc.T("Recorded %d attempt(s)", attempts))
+	}
+}
+
+// climbsNotFoundError is returned by findClimbs if the user or their team doesn't exist.
+type climbsNotFoundError string
+
+func (e climbsNotFoundError) Error() string { return string(e) }
+
+// writeClimbsError writes an error returned by findClimbs to w.
+func writeClimbsError(w http.ResponseWriter, loc *localizer, err error) {
+	if _, ok := err.(climbsNotFoundError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	} else {
+		http.Error(w, loc.T("Failed getting climbs: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// climbsLocation describes where a user's climbs are stored.
+type climbsLocation struct {
+	ref      *firestore.DocumentRef // team or user doc
+	path     firestore.FieldPath    // path to climbs map within ref
+	team     string                 // team ID, or empty if the climbs are in the user doc
+	name     string                 // user's name
+	left     bool                   // user left the team after reporting climbs
+	climbs   map[string]db.ClimbState
+	attempts map[string]int // boulder attempts keyed by route ID
+}
+
+// attemptsPath returns the path within l.ref to the number of attempts for route rid.
+// The attempts map is a sibling of the climbs map.
+func (l *climbsLocation) attemptsPath(rid string) firestore.FieldPath {
+	p := append(firestore.FieldPath{}, l.path[:len(l.path)-1]...)
+	return append(p, "attempts", rid)
+}
+
+// findClimbs returns the location of user uid's climbs. If the user is on a team,
+// their climbs are stored in the team doc. Otherwise, they're stored in the user doc.
+// Users who left teams after reporting climbs still have climbs in those teams' docs;
+// teamID (a team ID or invite code) can be supplied to get the user's climbs in one of
+// those teams instead of their current climbs. climbsNotFoundError is returned if the
+// user or team doesn't exist or if the user isn't listed on the team, with a message
+// localized using loc.
+func findClimbs(ctx context.Context, client *firestore.Client, comp, uid, teamID string,
+	loc *localizer) (*climbsLocation, error) {
+	if uid == "" {
+		return nil, climbsNotFoundError(loc.T("User not supplied"))
+	}
+	userRef := client.Collection(db.UserCollectionPath(comp)).Doc(uid)
+	var user db.User
+	if ok, err := getDocIfExists(ctx, userRef, &user); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, climbsNotFoundError(loc.T("User %q not found", uid))
+	}
+
+	if teamID == "" {
+		if user.Team == "" {
+			return &climbsLocation{
+				ref:      userRef,
+				path:     firestore.FieldPath{"climbs"},
+				name:     user.Name,
+				climbs:   user.Climbs,
+				attempts: user.Attempts,
+			}, nil
+		}
+		teamID = user.Team
+	}
+	teamRef, team, err := findTeam(ctx, client, comp, teamID)
+	if err == errTeamNotFound {
+		return nil, climbsNotFoundError(loc.T("Team %q not found", teamID))
+	} else if err != nil {
+		return nil, err
+	}
+	tu, ok := team.Users[uid]
+	if !ok {
+		return nil, climbsNotFoundError(loc.T("User %q not listed on team %q", uid, teamRef.ID))
+	}
+	return newTeamClimbsLocation(teamRef, uid, tu), nil
+}
+
+// findLeftClimbs returns the locations of user uid's climbs in teams that they left.
+func findLeftClimbs(ctx context.Context, client *firestore.Client, comp, uid string) ([]*climbsLocation, error) {
+	var locs []*climbsLocation
+	q := client.Collection(db.TeamCollectionPath(comp)).WherePath(firestore.FieldPath{"users", uid, "left"}, "==", true)
+	it := q.Documents(ctx)
+	defer it.Stop()
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed querying teams: %v", err)
+		}
+		var team db.Team
+		if err := snap.DataTo(&team); err != nil {
+			return nil, fmt.Errorf("failed decoding %v: %v", snap.Ref.Path, err)
+		}
+		locs = append(locs, newTeamClimbsLocation(snap.Ref, uid, team.Users[uid]))
+	}
+	return locs, nil
+}
+
+// newTeamClimbsLocation returns the location of user uid's climbs within the team doc at ref.
+func newTeamClimbsLocation(ref *firestore.DocumentRef, uid string, tu db.TeamUser) *climbsLocation {
+	return &climbsLocation{
+		ref:      ref,
+		path:     firestore.FieldPath{"users", uid, "climbs"},
+		team:     ref.ID,
+		name:     tu.Name,
+		left:     tu.Left,
+		climbs:   tu.Climbs,
+		attempts: tu.Attempts,
+	}
+}
+
+// getCorrections returns the corrections made to user uid's climbs, sorted by time.
+func getCorrections(ctx context.Context, client *firestore.Client, comp, uid string) ([]db.Correction, error) {
+	var corrs []db.Correction
+	it := client.Collection(db.CorrectionCollectionPath(comp)).Where("user", "==", uid).Documents(ctx)
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var c db.Correction
+		if err := snap.DataTo(&c); err != nil {
+			return nil, fmt.Errorf("failed decoding %v: %v", snap.Ref.Path, err)
+		}
+		corrs = append(corrs, c)
+	}
+	sort.Slice(corrs, func(i, j int) bool { return corrs[i].Time.Before(corrs[j].Time) })
+	return corrs, nil
+}
+
+// describeClimbs returns a line for each climb in climbs, ordered as in areas.
+// Recorded boulder attempts (keyed by route ID) are included from attempts, which
+// may be nil. Climbs of routes not present in areas are listed last.
+func describeClimbs(climbs map[string]db.ClimbState, attempts map[string]int,
+	areas []db.Area, loc *localizer) []string {
+	desc := func(id string, s db.ClimbState) string {
+		if n := attempts[id]; n > 0 && (s == db.Top || s == db.Zone) {
+			return loc.T("%v, %d attempt(s)", s, n)
+		}
+		return s.String()
+	}
+	var lines []string
+	seen := make(map[string]struct{})
+	for _, a := range areas {
+		for _, rt := range a.Routes {
+			if s, ok := climbs[rt.ID]; ok {
+				lines = append(lines, fmt.Sprintf("%v %q (%v): %v", rt.ID, rt.Name, a.Name, desc(rt.ID, s)))
+				seen[rt.ID] = struct{}{}
+			}
+		}
+	}
+	var unknown []string
+	for id, s := range climbs {
+		if _, ok := seen[id]; !ok {
+			unknown = append(unknown, loc.T("%v (unknown route): %v", id, desc(id, s)))
+		}
+	}
+	sort.Strings(unknown)
+	return append(lines, unknown...)
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestDescribeClimbs(t *testing.T) {
+	areas := []db.Area{
+		{Name: "Wall", Routes: []db.Route{{ID: "r1", Name: "One"}, {ID: "r2", Name: "Two"}}},
+		{Name: "Cave", Routes: []db.Route{{ID: "b1", Name: "Blob"}}},
+	}
+	climbs := map[string]db.ClimbState{
+		"b1":   db.Zone,
+		"r2":   db.Lead,
+		"gone": db.TopRope,
+	}
+	attempts := map[string]int{"b1": 3, "r2": 2}
+	got := describeClimbs(climbs, attempts, areas, defaultLocalizer)
+	want := []string{
+		`r2 "Two" (Wall): lead`,
+		`b1 "Blob" (Cave): zone, 3 attempt(s)`,
+		"gone (unknown route): tr",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeClimbs(...) = %q; want %q", got, want)
+	}
+}
+
+func TestFindClimbs(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	ff.set("users/solo", map[string]interface{}{"name": "Solo", "climbs": map[string]interface{}{"r1": 1}})
+	ff.set("users/mover", map[string]interface{}{"name": "Mover", "team": "t2"})
+	ff.set("users/both", map[string]interface{}{"name": "Both", "team": "t2"})
+	ff.set("teams/t1", map[string]interface{}{"name": "Old", "users": map[string]interface{}{
+		"mover": map[string]interface{}{"name": "Mover", "left": true, "climbs": map[string]interface{}{"r1": 2}},
+		"both":  map[string]interface{}{"name": "Both", "left": true, "climbs": map[string]interface{}{"r2": 1}},
+	}})
+	ff.set("teams/t2", map[string]interface{}{"name": "New", "users": map[string]interface{}{
+		"mover": map[string]interface{}{"name": "Mover", "climbs": map[string]interface{}{}},
+		"both":  map[string]interface{}{"name": "Both", "climbs": map[string]interface{}{"r1": 1}},
+	}})
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		uid    string
+		team   string // team to pass to findClimbs
+		ref    string // ID of doc containing climbs, or empty if error expected
+		left   bool
+		climbs map[string]db.ClimbState
+	}{
+		{"solo", "", "solo", false, map[string]db.ClimbState{"r1": db.Lead}},
+		// The current team is used even if the user only has climbs in a team they left.
+		{"mover", "", "t2", false, map[string]db.ClimbState{}},
+		{"both", "", "t2", false, map[string]db.ClimbState{"r1": db.Lead}},
+		// Climbs in teams that the user left must be requested explicitly.
+		{"mover", "t1", "t1", true, map[string]db.ClimbState{"r1": db.TopRope}},
+		{"both", "t1", "t1", true, map[string]db.ClimbState{"r2": db.Lead}},
+		{"both", "t2", "t2", false, map[string]db.ClimbState{"r1": db.Lead}},
+		{"solo", "t1", "", false, nil},
+		{"both", "t3", "", false, nil},
+		{"missing", "", "", false, nil},
+	} {
+		loc, err := findClimbs(ctx, client, "", tc.uid, tc.team, defaultLocalizer)
+		if tc.ref == "" {
+			if err == nil {
+				t.Errorf("findClimbs(%q, %q) unexpectedly returned %v", tc.uid, tc.team, loc.ref.Path)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("findClimbs(%q, %q) failed: %v", tc.uid, tc.team, err)
+			continue
+		}
+		if loc.ref.ID != tc.ref || loc.left != tc.left || !reflect.DeepEqual(loc.climbs, tc.climbs) {
+			t.Errorf("findClimbs(%q, %q) = (%v, left=%v, %v); want (%v, left=%v, %v)",
+				tc.uid, tc.team, loc.ref.ID, loc.left, loc.climbs, tc.ref, tc.left, tc.climbs)
+		}
+	}
+}
+
+func TestSetClimbInvalidatesStandings(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	addFakeCompetition(ff, 2, 3, 0)
+	// The fake doesn't support quoted field paths, so use a user ID without a hyphen.
+	ff.set("teams/t00000", map[string]interface{}{"name": "Team 0", "users": map[string]interface{}{
+		"ann": map[string]interface{}{"name": "Ann", "climbs": map[string]interface{}{"r1": 1}},
+	}})
+	ff.set("users/ann", map[string]interface{}{"name": "Ann", "team": "t00000"})
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	ctx := context.Background()
+	comp := db.DefaultCompetition
+	if _, _, _, err := getStandings(ctx, client, comp, time.Hour); err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+
+	params := url.Values{"climbUser": {"ann"}, "climbRoute": {"r0"}, "climbState": {"tr"}, "climbReason": {"Test"}}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(params.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handleSetClimb(ctx, w, req, client, comp)
+	if w.Code != 200 {
+		t.Fatalf("handleSetClimb returned %d: %v", w.Code, w.Body.String())
+	}
+
+	// The standings should be recomputed to include the new climb.
+	_, users, _, err := getStandings(ctx, client, comp, time.Hour)
+	if err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+	for _, us := range users {
+		if us.UID == "ann" {
+			if st := us.Climbs["r0"]; st != db.TopRope {
+				t.Errorf("User ann's r0 climb is %v in standings; want %v", st, db.TopRope)
+			}
+			return
+		}
+	}
+	t.Error("User ann not in standings")
+}
+
+func TestSetClimbAttempts(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	addFakeCompetition(ff, 2, 3, 0)
+	ff.set("teams/t00000", map[string]interface{}{"name": "Team 0", "users": map[string]interface{}{
+		"ann": map[string]interface{}{"name": "Ann", "climbs": map[string]interface{}{"r1": 1}},
+	}})
+	ff.set("users/ann", map[string]interface{}{"name": "Ann", "team": "t00000"})
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	ctx := context.Background()
+	post := func(state, attempts string) int {
+		params := url.Values{"climbUser": {"ann"}, "climbRoute": {"r0"}, "climbState": {state},
+			"climbAttempts": {attempts}, "climbReason": {"Test"}}
+		req := httptest.NewRequest("POST", "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handleSetClimb(ctx, w, req, client, db.DefaultCompetition)
+		return w.Code
+	}
+	getAttempts := func() map[string]int {
+		var team db.Team
+		if err := db.GetDoc(ctx, client.Doc("teams/t00000"), &team); err != nil {
+			t.Fatal("Failed getting team: ", err)
+		}
+		return team.Users["ann"].Attempts
+	}
+
+	for _, tc := range []struct {
+		state, attempts string
+		code            int
+		want            map[string]int // expected attempts after request
+	}{
+		{"top", "4", 200, map[string]int{"r0": 4}},
+		{"top", "4", 400, map[string]int{"r0": 4}}, // unchanged
+		{"top", "6", 200, map[string]int{"r0": 6}}, // same state, but new attempts
+		{"flash", "2", 400, map[string]int{"r0": 6}},
+		{"zone", "0", 400, map[string]int{"r0": 6}},
+		{"zone", "abc", 400, map[string]int{"r0": 6}},
+		{"flash", "", 200, map[string]int{}}, // attempts are dropped
+	} {
+		if code := post(tc.state, tc.attempts); code != tc.code {
+			t.Errorf("Setting %v with %q attempts returned %d; want %d", tc.state, tc.attempts, code, tc.code)
+		}
+		if got := getAttempts(); len(got) != len(tc.want) || (len(got) > 0 && !reflect.DeepEqual(got, tc.want)) {
+			t.Errorf("After setting %v with %q attempts, attempts are %v; want %v", tc.state, tc.attempts, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// csvColumn describes a column in CSV data.
+type csvColumn struct {
+	name     string   // lowercase canonical name, used as a key in rowDestFunc maps
+	aliases  []string // lowercase alternate names accepted in header rows
+	optional bool     // column may be omitted from the input
+	def      string   // value used for each row if an optional column is omitted
+}
+
+// unknownColumnPolicy describes how readCSV handles columns not described by a csvColumn.
+type unknownColumnPolicy int
+
+const (
+	// rejectUnknown makes readCSV return an error.
+	rejectUnknown unknownColumnPolicy = iota
+	// ignoreUnknown makes readCSV silently skip the column.
+	ignoreUnknown
+	// preserveUnknown makes readCSV copy the column's values to the *map[string]string
+	// stored at extraColsKey in the row's destination map.
+	preserveUnknown
+)
+
+// extraColsKey is used in rowDestFunc maps to hold a *map[string]string that receives
+// unknown columns' values, keyed by lowercase column name.
+const extraColsKey = "*"
+
+// rowDestFunc is passed to readCSV and returns a map from column name to
+// destination (*string, *int, *float64, or *bool) for data in a new row.
+type rowDestFunc func() map[string]interface{}
+
+// readCSV returns CSV data from r. The first row should contain column names,
+// which are matched against cols' names and aliases case-insensitively.
+// f is invoked for each row and should return a map from cols' names to
+// destinations for their values.
+func readCSV(r io.Reader, cols []csvColumn, unknown unknownColumnPolicy, f rowDestFunc) error {
+	cr := csv.NewReader(r)
+	head, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %v", err)
+	}
+
+	// Map from lowercase canonical names and aliases to column descriptions.
+	known := make(map[string]*csvColumn)
+	for i := range cols {
+		c := &cols[i]
+		known[c.name] = c
+		for _, a := range c.aliases {
+			known[a] = c
+		}
+	}
+
+	// Determine the column described by each field in the header.
+	headCols := make([]*csvColumn, len(head))
+	names := make([]string, len(head)) // lowercase names from header
+	seen := make(map[string]struct{})  // canonical names of seen columns
+	for i, h := range head {
+		names[i] = strings.ToLower(strings.TrimSpace(h))
+		key := names[i]
+		if c, ok := known[names[i]]; ok {
+			headCols[i] = c
+			key = c.name
+		} else if unknown == rejectUnknown {
+			return fmt.Errorf("unknown column %q", h)
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate column %q", h)
+		}
+		seen[key] = struct{}{}
+	}
+
+	// Check that all required columns were supplied.
+	var missing []string
+	var omitted []*csvColumn // optional columns that weren't supplied
+	for i := range cols {
+		if _, ok := seen[cols[i].name]; !ok {
+			if cols[i].optional {
+				omitted = append(omitted, &cols[i])
+			} else {
+				missing = append(missing, cols[i].name)
+			}
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("missing column(s) %q", missing)
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed reading row: %v", err)
+		}
+
+		// Iterate over columns and copy values to the appropriate destination.
+		dstMap := f()
+		for i, val := range row {
+			if c := headCols[i]; c != nil {
+				if err := setCSVValue(dstMap, c.name, val); err != nil {
+					return fmt.Errorf("%v in %q", err, row)
+				}
+			} else if unknown == preserveUnknown {
+				dst, ok := dstMap[extraColsKey].(*map[string]string)
+				if !ok {
+					return fmt.Errorf("no destination for unknown column %q", head[i])
+				}
+				if val == "" {
+					continue
+				}
+				if *dst == nil {
+					*dst = make(map[string]string)
+				}
+				(*dst)[names[i]] = val
+			}
+		}
+		for _, c := range omitted {
+			if err := setCSVValue(dstMap, c.name, c.def); err != nil {
+				return fmt.Errorf("%v (default)", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setCSVValue parses val and stores it in dstMap's destination for the named column.
+func setCSVValue(dstMap map[string]interface{}, name, val string) error {
+	var err error
+	switch td := dstMap[name].(type) {
+	case *string:
+		*td = val
+	case *int:
+		if val == "" {
+			*td = 0
+		} else if *td, err = strconv.Atoi(val); err != nil {
+			return fmt.Errorf("failed to parse %q for column %q: %v", val, name, err)
+		}
+	case *float64:
+		if val == "" {
+			*td = 0
+		} else if *td, err = strconv.ParseFloat(val, 64); err != nil {
+			return fmt.Errorf("failed to parse %q for column %q: %v", val, name, err)
+		}
+	case *bool:
+		switch strings.ToLower(val) {
+		case "", "0", "f", "false", "n", "no":
+			*td = false
+		case "1", "t", "true", "y", "yes":
+			*td = true
+		default:
+			return fmt.Errorf("failed to parse %q for column %q as bool", val, name)
+		}
+	default:
+		return fmt.Errorf("unsupported type %T for column %q", td, name)
+	}
+	return nil
+}
+
+// writeCSV is the inverse of readCSV. It writes a header row containing cols' names
+// to w, followed by n rows. f is invoked for each row and should return a map from
+// column names to sources for their values. Zero values are written as empty strings.
+// If any rows' maps contain extra columns at extraColsKey, they are written in
+// additional alphabetized columns.
+func writeCSV(w io.Writer, cols []csvColumn, n int, f func(i int) map[string]interface{}) error {
+	srcMaps := make([]map[string]interface{}, n)
+	extraSeen := make(map[string]struct{})
+	for i := range srcMaps {
+		srcMaps[i] = f(i)
+		if extra, ok := srcMaps[i][extraColsKey].(*map[string]string); ok {
+			for name := range *extra {
+				extraSeen[name] = struct{}{}
+			}
+		}
+	}
+	extraNames := make([]string, 0, len(extraSeen))
+	for name := range extraSeen {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+
+	cw := csv.NewWriter(w)
+	head := make([]string, 0, len(cols)+len(extraNames))
+	for _, c := range cols {
+		head = append(head, c.name)
+	}
+	head = append(head, extraNames...)
+	if err := cw.Write(head); err != nil {
+		return err
+	}
+
+	for _, srcMap := range srcMaps {
+		row := make([]string, 0, len(head))
+		for _, c := range cols {
+			switch ts := srcMap[c.name].(type) {
+			case *string:
+				row = append(row, *ts)
+			case *int:
+				if *ts != 0 {
+					row = append(row, strconv.Itoa(*ts))
+				} else {
+					row = append(row, "")
+				}
+			case *float64:
+				if *ts != 0 {
+					row = append(row, strconv.FormatFloat(*ts, 'f', -1, 64))
+				} else {
+					row = append(row, "")
+				}
+			case *bool:
+				if *ts {
+					row = append(row, "true")
+				} else {
+					row = append(row, "")
+				}
+			default:
+				return fmt.Errorf("unsupported type %T for column %q", ts, c.name)
+			}
+		}
+		var extra map[string]string
+		if p, ok := srcMap[extraColsKey].(*map[string]string); ok {
+			extra = *p
+		}
+		for _, name := range extraNames {
+			row = append(row, extra[name])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// csvTestRow is used to hold data read or written by readCSV and writeCSV.
+type csvTestRow struct {
+	Name  string
+	Count int
+	Ratio float64
+	Flag  bool
+	Extra map[string]string
+}
+
+// dests returns a map for use with readCSV and writeCSV.
+func (r *csvTestRow) dests() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       &r.Name,
+		"count":      &r.Count,
+		"ratio":      &r.Ratio,
+		"flag":       &r.Flag,
+		extraColsKey: &r.Extra,
+	}
+}
+
+var csvTestCols = []csvColumn{
+	{name: "name", aliases: []string{"title"}},
+	{name: "count", optional: true, def: "3"},
+	{name: "ratio", optional: true},
+	{name: "flag", aliases: []string{"enabled"}, optional: true, def: "yes"},
+}
+
+func TestReadCSV(t *testing.T) {
+	for _, tc := range []struct {
+		in      string              // input CSV data (including header row)
+		unknown unknownColumnPolicy // policy for unknown columns
+		out     []csvTestRow        // expected output; nil if error is expected
+	}{
+		{"name,count,ratio,flag\na,1,0.5,true\nb,,,no\n", rejectUnknown, []csvTestRow{
+			{Name: "a", Count: 1, Ratio: 0.5, Flag: true},
+			{Name: "b"},
+		}},
+		{"Title,ENABLED\na,0\n", rejectUnknown, []csvTestRow{{Name: "a", Count: 3}}},
+		{"name\na\n", rejectUnknown, []csvTestRow{{Name: "a", Count: 3, Flag: true}}},
+		{"name,notes\na,foo\n", ignoreUnknown, []csvTestRow{{Name: "a", Count: 3, Flag: true}}},
+		{"name,Notes,x\na,foo,\n", preserveUnknown, []csvTestRow{
+			{Name: "a", Count: 3, Flag: true, Extra: map[string]string{"notes": "foo"}},
+		}},
+		{"name,notes\na,foo\n", rejectUnknown, nil},       // unknown column
+		{"count\n1\n", rejectUnknown, nil},                // missing required column
+		{"name,title\na,b\n", rejectUnknown, nil},         // duplicate column via alias
+		{"name,ratio\na,abc\n", rejectUnknown, nil},       // unparseable float
+		{"name,flag\na,maybe\n", rejectUnknown, nil},      // unparseable bool
+		{"name,notes,notes\na,b,c\n", ignoreUnknown, nil}, // duplicate unknown column
+	} {
+		var rows []csvTestRow
+		if err := readCSV(strings.NewReader(tc.in), csvTestCols, tc.unknown, func() map[string]interface{} {
+			rows = append(rows, csvTestRow{})
+			return rows[len(rows)-1].dests()
+		}); err != nil {
+			if tc.out != nil {
+				t.Errorf("readCSV(%q) failed: %v", tc.in, err)
+			}
+		} else if tc.out == nil {
+			t.Errorf("readCSV(%q) unexpectedly succeeded with %+v", tc.in, rows)
+		} else if !reflect.DeepEqual(rows, tc.out) {
+			t.Errorf("readCSV(%q) = %+v; want %+v", tc.in, rows, tc.out)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []csvTestRow{
+		{Name: "a", Count: 1, Ratio: 0.25, Flag: true, Extra: map[string]string{"notes": "foo"}},
+		{Name: "b", Extra: map[string]string{"color": "red"}},
+	}
+	var b bytes.Buffer
+	if err := writeCSV(&b, csvTestCols, len(rows), func(i int) map[string]interface{} {
+		return rows[i].dests()
+	}); err != nil {
+		t.Fatal("writeCSV failed: ", err)
+	}
+	const want = "name,count,ratio,flag,color,notes\n" +
+		"a,1,0.25,true,,foo\n" +
+		"b,,,,red,\n"
+	if b.String() != want {
+		t.Errorf("writeCSV wrote %q; want %q", b.String(), want)
+	}
+}
@@ -9,56 +9,103 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"cloud.google.com/go/firestore"
-	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/derat/ascenso/go/db"
 )
 
 // handleEmptyTeams handles an "emptyTeams" POST request.
-// It deletes empty teams from Cloud Firestore.
-func handleEmptyTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	var deleted []db.Team
+// It deletes empty teams from Cloud Firestore. Teams that are modified (e.g. joined)
+// after they're loaded are reported and left alone.
+func handleEmptyTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	// Load all teams with a single query.
+	var empty []emptyTeam
+	if err := loadCollection(ctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var team db.Team
+		if err := snap.DataTo(&team); err != nil {
+			return err
+		}
+		log.Printf("Team %s (%q) has %d user(s)", snap.Ref.ID, team.Name, len(team.Users))
+		if len(team.Users) == 0 {
+			empty = append(empty, emptyTeam{snap.Ref, snap.UpdateTime, team})
+		}
+		return nil
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading teams: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-	it := client.Collection(db.TeamCollectionPath).DocumentRefs(ctx)
-	for {
-		ref, err := it.Next()
-		if err == iterator.Done {
-			break
+	// Delete the teams and their invites in as few batches as possible.
+	var deleted, changed []db.Team
+	for start := 0; start < len(empty); start += maxBatchWrites / 2 { // two deletes per team
+		end := start + maxBatchWrites/2
+		if end > len(empty) {
+			end = len(empty)
+		}
+		err := deleteEmptyTeams(ctx, client, comp, empty[start:end])
+		if status.Code(err) == codes.FailedPrecondition {
+			// At least one team was modified after it was loaded, and the whole batch
+			// was rejected. Retry each team separately to find the ones that changed.
+			for _, et := range empty[start:end] {
+				if err := deleteEmptyTeams(ctx, client, comp, []emptyTeam{et}); status.Code(err) == codes.FailedPrecondition {
+					log.Printf("%s was modified; not deleting it", et.ref.Path)
+					changed = append(changed, et.team)
+				} else if err != nil {
+					http.Error(w, loc.T("Failed deleting teams: %v", err), http.StatusInternalServerError)
+					return
+				} else {
+					deleted = append(deleted, et.team)
+				}
+			}
+			continue
 		} else if err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting team ref: %v", err), http.StatusInternalServerError)
+			http.Error(w, loc.T("Failed deleting teams: %v", err), http.StatusInternalServerError)
 			return
 		}
-		var team db.Team
-		if err := db.GetDoc(ctx, ref, &team); err != nil {
-			http.Error(w, fmt.Sprintf("Failed getting team doc: %v", err), http.StatusInternalServerError)
-			return
+		for _, et := range empty[start:end] {
+			deleted = append(deleted, et.team)
 		}
+	}
 
-		log.Printf("Team %s (%q) has %d user(s)", ref.ID, team.Name, len(team.Users))
-		if len(team.Users) != 0 {
-			continue
+	fmt.Fprintln(w, loc.T("Deleted %d empty team(s)", len(deleted)))
+	for _, t := range deleted {
+		fmt.Fprintf(w, "%q\n", t.Name)
+	}
+	if len(changed) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, loc.T("Skipped %d team(s) that changed while deleting", len(changed)))
+		for _, t := range changed {
+			fmt.Fprintf(w, "%q\n", t.Name)
 		}
+	}
+}
 
-		batch := client.Batch()
-		log.Printf("Deleting %s (%+v)", ref.Path, team)
-		batch.Delete(ref)
+// emptyTeam describes a team without members that was loaded by handleEmptyTeams.
+type emptyTeam struct {
+	ref     *firestore.DocumentRef
+	updated time.Time // team doc's update time when it was loaded
+	team    db.Team
+}
+
+// deleteEmptyTeams deletes teams and their invites in a single batch.
+// If any team was modified since it was loaded, nothing is deleted and an error
+// with code codes.FailedPrecondition is returned.
+func deleteEmptyTeams(ctx context.Context, client *firestore.Client, comp string, teams []emptyTeam) error {
+	batch := client.Batch()
+	for _, et := range teams {
+		log.Printf("Deleting %s (%+v)", et.ref.Path, et.team)
+		batch.Delete(et.ref, firestore.LastUpdateTime(et.updated))
 
 		// Also delete the team's invite doc so it won't be orphaned.
-		inviteRef := client.Collection(db.InviteCollectionPath).Doc(team.Invite)
+		inviteRef := client.Collection(db.InviteCollectionPath(comp)).Doc(et.team.Invite)
 		log.Printf("Deleting %s", inviteRef.Path)
 		batch.Delete(inviteRef)
-
-		if _, err := batch.Commit(ctx); err != nil {
-			http.Error(w, fmt.Sprintf("Failed deleting team: %v", err), http.StatusInternalServerError)
-			return
-		}
-		deleted = append(deleted, team)
-	}
-
-	fmt.Fprintf(w, "Deleted %d empty team(s)\n", len(deleted))
-	for _, t := range deleted {
-		fmt.Fprintf(w, "%q\n", t.Name)
 	}
+	_, err := batch.Commit(ctx)
+	return err
 }
@@ -0,0 +1,98 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestHandleEmptyTeams(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	// Use enough empty teams to require multiple batches.
+	const numTeams = 1000
+	addFakeCompetition(ff, numTeams, 5, 2)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	w := httptest.NewRecorder()
+	handleEmptyTeams(context.Background(), w, httptest.NewRequest("POST", "/", nil), client, db.DefaultCompetition)
+	if w.Code != 200 {
+		t.Fatalf("handleEmptyTeams returned %d: %v", w.Code, w.Body.String())
+	}
+	if got, want := ff.count("teams"), numTeams/2; got != want {
+		t.Errorf("%d team(s) remain; want %d", got, want)
+	}
+	if got, want := ff.count("invites"), numTeams/2; got != want {
+		t.Errorf("%d invite(s) remain; want %d", got, want)
+	}
+}
+
+func TestHandleEmptyTeams_Modified(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	addFakeCompetition(ff, 10, 5, 2) // t00000, t00002, etc. are empty
+
+	// Simulate someone joining an empty team after it was loaded.
+	ff.beforeCommit = func() {
+		ff.beforeCommit = nil
+		ff.set("teams/t00004", map[string]interface{}{
+			"name":   "Team 4",
+			"invite": "000004",
+			"users":  map[string]interface{}{"new": map[string]interface{}{"name": "New", "climbs": map[string]interface{}{}}},
+		})
+	}
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	w := httptest.NewRecorder()
+	handleEmptyTeams(context.Background(), w, httptest.NewRequest("POST", "/", nil), client, db.DefaultCompetition)
+	if w.Code != 200 {
+		t.Fatalf("handleEmptyTeams returned %d: %v", w.Code, w.Body.String())
+	}
+	if got, want := ff.count("teams"), 6; got != want {
+		t.Errorf("%d team(s) remain; want %d", got, want)
+	}
+	if got, want := ff.count("invites"), 6; got != want {
+		t.Errorf("%d invite(s) remain; want %d", got, want)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "Deleted 4 empty team(s)") ||
+		!strings.Contains(body, "Skipped 1 team(s) that changed while deleting\n\"Team 4\"") {
+		t.Errorf("handleEmptyTeams wrote unexpected output:\n%s", body)
+	}
+}
+
+func BenchmarkHandleEmptyTeams(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, numTeams := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("teams=%d", numTeams), func(b *testing.B) {
+			ff, client := newFakeFirestore(b, benchLatency)
+			var elapsed time.Duration
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				addFakeCompetition(ff, numTeams, 5, 10) // 10% of teams are empty
+				b.StartTimer()
+				start := time.Now()
+				w := httptest.NewRecorder()
+				handleEmptyTeams(context.Background(), w, httptest.NewRequest("POST", "/", nil), client, db.DefaultCompetition)
+				if w.Code != 200 {
+					b.Fatalf("handleEmptyTeams returned %d: %v", w.Code, w.Body.String())
+				}
+				elapsed += time.Since(start)
+			}
+			b.ReportMetric(float64(numTeams*b.N)/elapsed.Seconds(), "teams/s")
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handlePostAreasCSV handles an "areasCsv" POST request.
+// It writes the current area data in the format accepted by readAreas.
+func handlePostAreasCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		http.Error(w, loc.T("Failed getting sorted data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	areas, _ := sorted.Split()
+	setCSVHeaders(w.Header(), "areas.csv")
+	if err := writeAreas(w, areas); err != nil {
+		http.Error(w, loc.T("Failed writing areas: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// handlePostRoutesCSV handles a "routesCsv" POST request.
+// It writes the current route data in the format accepted by readRoutes.
+func handlePostRoutesCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		http.Error(w, loc.T("Failed getting sorted data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	_, routes := sorted.Split()
+	setCSVHeaders(w.Header(), "routes.csv")
+	if err := writeRoutes(w, routes); err != nil {
+		http.Error(w, loc.T("Failed writing routes: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// writeAreas writes areas to w in CSV format, preceded by a header row.
+func writeAreas(w io.Writer, areas []db.Area) error {
+	return writeCSV(w, areaCols, len(areas), func(i int) map[string]interface{} {
+		return areaDests(&areas[i])
+	})
+}
+
+// writeRoutes writes routes to w in CSV format, preceded by a header row.
+func writeRoutes(w io.Writer, routes []db.Route) error {
+	return writeCSV(w, routeCols, len(routes), func(i int) map[string]interface{} {
+		return routeDests(&routes[i])
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// scoresExportVersion is the version of the format written by handleScoresJSON.
+// It must be incremented whenever fields are removed or their meanings change.
+// New fields may be added without incrementing it.
+const scoresExportVersion = 1
+
+// handleScoresJSON handles a "scoresJson" POST request.
+// It writes the current competition's teams, users, and climbs as a JSON attachment.
+func handleScoresJSON(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	// Archives don't record verifications, so per-climb points can't be reconstructed.
+	if r.FormValue("archive") != "" {
+		http.Error(w, loc.T("JSON export doesn't support archives"), http.StatusBadRequest)
+		return
+	}
+
+	// The precomputed standings lack user IDs and verifications, so compute the scores
+	// directly to get per-climb points that are consistent with the totals.
+	now := time.Now()
+	sd, err := getScores(ctx, client, comp)
+	if err != nil {
+		http.Error(w, loc.T("Failed loading scores: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	exp := newScoresExport(comp, now, sd.teams, sd.sorted.Areas, sd.verifs)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=scores.json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(exp); err != nil {
+		http.Error(w, loc.T("Failed writing scores: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// scoresExport is the top-level object written by handleScoresJSON.
+type scoresExport struct {
+	Version     int                `json:"version"` // scoresExportVersion
+	Competition string             `json:"competition"`
+	Time        time.Time          `json:"time"` // time at which scores were computed
+	Teams       []teamScoresExport `json:"teams"`
+}
+
+// teamScoresExport describes a team within a scoresExport.
+type teamScoresExport struct {
+	Name      string             `json:"name"`
+	Score     int                `json:"score"`
+	NumClimbs int                `json:"numClimbs"`
+	Height    int                `json:"height"` // feet
+	Users     []userScoresExport `json:"users"`
+}
+
+// userScoresExport describes a climber within a teamScoresExport.
+type userScoresExport struct {
+	Name      string        `json:"name"`
+	Score     int           `json:"score"`
+	NumClimbs int           `json:"numClimbs"`
+	Height    int           `json:"height"` // feet
+	Climbs    []climbExport `json:"climbs"`
+}
+
+// climbExport describes a climb within a userScoresExport.
+type climbExport struct {
+	Route    string `json:"route"` // route ID
+	Name     string `json:"name"`
+	Area     string `json:"area"` // area ID
+	AreaName string `json:"areaName"`
+	Grade    string `json:"grade,omitempty"`
+	State    string `json:"state"` // db.ClimbState name, e.g. "lead" or "tr"
+	Points   int    `json:"points"`
+	// Verification contains the climb's db.VerificationStatus name (e.g. "approved") if
+	// the route requires verification. Climbs that haven't been approved get 0 points.
+	Verification string `json:"verification,omitempty"`
+}
+
+// newScoresExport returns a scoresExport describing competition comp's teams, as
+// returned by getScores at time t. Climbs are listed in the order in which they
+// appear in areas, and verifs (keyed by user ID) is used to compute their points.
+func newScoresExport(comp string, t time.Time, teams []teamSummary, areas []db.Area,
+	verifs map[string]db.UserVerifications) *scoresExport {
+	exp := scoresExport{
+		Version:     scoresExportVersion,
+		Competition: comp,
+		Time:        t,
+		Teams:       make([]teamScoresExport, 0, len(teams)),
+	}
+	for _, ts := range teams {
+		te := teamScoresExport{
+			Name:      ts.Name,
+			Score:     ts.Score,
+			NumClimbs: ts.NumClimbs,
+			Height:    ts.Height,
+			Users:     make([]userScoresExport, 0, len(ts.Users)),
+		}
+		for _, us := range ts.Users {
+			ue := userScoresExport{
+				Name:      us.Name,
+				Score:     us.Score,
+				NumClimbs: us.NumClimbs,
+				Height:    us.Height,
+				Climbs:    []climbExport{},
+			}
+			for _, a := range areas {
+				for _, rt := range a.Routes {
+					state, ok := us.Climbs[rt.ID]
+					if !ok || state == db.NotClimbed {
+						continue
+					}
+					verif := verifs[us.UID].Climbs[rt.ID]
+					ce := climbExport{
+						Route:    rt.ID,
+						Name:     rt.Name,
+						Area:     a.ID,
+						AreaName: a.Name,
+						Grade:    rt.Grade,
+						State:    state.String(),
+					}
+					ce.Points, _ = climbPoints(rt, state, verif)
+					if rt.Verify {
+						ce.Verification = verif.GetStatus(state).String()
+					}
+					ue.Climbs = append(ue.Climbs, ce)
+				}
+			}
+			te.Users = append(te.Users, ue)
+		}
+		exp.Teams = append(exp.Teams, te)
+	}
+	return &exp
+}
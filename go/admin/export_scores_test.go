@@ -0,0 +1,59 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestNewScoresExport(t *testing.T) {
+	areas := []db.Area{
+		{ID: "a1", Name: "Area 1", Routes: []db.Route{
+			{ID: "r1", Name: "Route 1", Grade: "5.10a", Lead: 10, TR: 5},
+			{ID: "r2", Name: "Route 2", Grade: "5.11a", Lead: 20, TR: 10, Verify: true},
+		}},
+		{ID: "a2", Name: "Area 2", Routes: []db.Route{
+			{ID: "b1", Name: "Boulder 1", Grade: "V3", Flash: 12, Top: 10, Zone: 4},
+		}},
+	}
+	teams := []teamSummary{{"Team A", 34, 2, 0, 0, []userSummary{
+		{UID: "u1", Name: "User 1", Score: 14, NumClimbs: 1,
+			Climbs: map[string]db.ClimbState{"b1": db.Zone, "r1": db.Lead, "r2": db.TopRope}},
+		{UID: "u2", Name: "User 2", Score: 20, NumClimbs: 1,
+			Climbs: map[string]db.ClimbState{"r2": db.Lead}},
+	}}}
+	verifs := map[string]db.UserVerifications{
+		"u2": {Climbs: map[string]db.Verification{"r2": {State: db.Lead, Status: db.Approved}}},
+	}
+
+	now := time.Unix(1700000000, 0)
+	got := newScoresExport("comp", now, teams, areas, verifs)
+	want := &scoresExport{
+		Version:     scoresExportVersion,
+		Competition: "comp",
+		Time:        now,
+		Teams: []teamScoresExport{{Name: "Team A", Score: 34, NumClimbs: 2, Users: []userScoresExport{
+			{Name: "User 1", Score: 14, NumClimbs: 1, Climbs: []climbExport{
+				{Route: "r1", Name: "Route 1", Area: "a1", AreaName: "Area 1", Grade: "5.10a",
+					State: "lead", Points: 10},
+				{Route: "r2", Name: "Route 2", Area: "a1", AreaName: "Area 1", Grade: "5.11a",
+					State: "tr", Points: 0, Verification: "pending"},
+				{Route: "b1", Name: "Boulder 1", Area: "a2", AreaName: "Area 2", Grade: "V3",
+					State: "zone", Points: 4},
+			}},
+			{Name: "User 2", Score: 20, NumClimbs: 1, Climbs: []climbExport{
+				{Route: "r2", Name: "Route 2", Area: "a1", AreaName: "Area 1", Grade: "5.11a",
+					State: "lead", Points: 20, Verification: "approved"},
+			}},
+		}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newScoresExport returned %+v; want %+v", got, want)
+	}
+}
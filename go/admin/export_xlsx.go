@@ -0,0 +1,147 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleScoresXLSX handles a "scoresXlsx" POST request.
+// It writes an XLSX workbook with sheets describing teams, users, per-route ascent
+// counts, and routes. If the "archive" parameter is set, the named archive is used.
+func handleScoresXLSX(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	teams, users, updated, err := loadScores(ctx, r, client, comp)
+	if err != nil {
+		http.Error(w, loc.T("Failed loading scores: %v", err), loadErrorStatus(err))
+		return
+	}
+	sorted, err := loadSortedData(ctx, r, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), loadErrorStatus(err))
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+
+	sheets := []xlsxSheet{
+		{"Teams", numericRows(makeTeamRecords(teams, cfg.GetTeamSize()), 3)},
+		{"Users", numericRows(makeUserRecords(users), 3)},
+		{"Route ascents", makeRouteAscentRows(users, sorted.Areas)},
+		{"Routes", makeRouteRows(sorted.Areas)},
+	}
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=scores.xlsx")
+	setLastModified(w.Header(), updated)
+	if err := writeXLSX(w, sheets); err != nil {
+		http.Error(w, loc.T("Failed writing workbook: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// loadSortedData returns sorted area and route data for r. If the "archive" parameter
+// is set, the data is read from the named archive. Otherwise, competition comp's
+// current data is returned.
+func loadSortedData(ctx context.Context, r *http.Request, client *firestore.Client, comp string) (
+	db.SortedData, error) {
+	if id := r.FormValue("archive"); id != "" {
+		arch, err := getArchive(ctx, client, id)
+		if err != nil {
+			return db.SortedData{}, err
+		}
+		return arch.SortedData, nil
+	}
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		return sorted, fmt.Errorf("failed getting sorted data: %v", err)
+	}
+	return sorted, nil
+}
+
+// numericRows converts CSV records (including a header) to spreadsheet rows.
+// The last numCols columns of each non-header record are converted to ints.
+func numericRows(recs [][]string, numCols int) [][]interface{} {
+	rows := make([][]interface{}, len(recs))
+	for i, rec := range recs {
+		rows[i] = make([]interface{}, len(rec))
+		for j, val := range rec {
+			rows[i][j] = val
+			if i > 0 && j >= len(rec)-numCols {
+				if n, err := strconv.Atoi(val); err == nil {
+					rows[i][j] = n
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// makeRouteAscentRows returns spreadsheet rows (including a header) with the number of
+// users who reported each state for each route in areas. Unverified climbs are included.
+func makeRouteAscentRows(users []userSummary, areas []db.Area) [][]interface{} {
+	states := []db.ClimbState{db.Lead, db.TopRope, db.Flash, db.Top, db.Zone}
+	counts := make(map[string]map[db.ClimbState]int) // keyed by route ID
+	for _, u := range users {
+		for id, s := range u.Climbs {
+			if counts[id] == nil {
+				counts[id] = make(map[db.ClimbState]int)
+			}
+			counts[id][s]++
+		}
+	}
+
+	head := []interface{}{"area", "route", "grade"}
+	for _, s := range states {
+		head = append(head, s.String())
+	}
+	rows := [][]interface{}{append(head, "ascents")}
+	for _, a := range areas {
+		for _, rt := range a.Routes {
+			row := []interface{}{a.Name, rt.Name, rt.Grade}
+			var ascents int
+			for _, s := range states {
+				n := counts[rt.ID][s]
+				row = append(row, n)
+				if s != db.Zone {
+					ascents += n
+				}
+			}
+			rows = append(rows, append(row, ascents))
+		}
+	}
+	return rows
+}
+
+// makeRouteRows returns spreadsheet rows (including a header) describing the routes in areas.
+func makeRouteRows(areas []db.Area) [][]interface{} {
+	rows := [][]interface{}{{
+		"area", "id", "name", "grade", "type", "lead", "tr", "flash", "top", "zone",
+		"height", "pitches", "setter", "color", "mpid", "verify", "description",
+	}}
+	for _, a := range areas {
+		for _, rt := range a.Routes {
+			var verify string
+			if rt.Verify {
+				verify = "true"
+			}
+			rows = append(rows, []interface{}{
+				a.Name, rt.ID, rt.Name, rt.Grade, string(rt.Type), rt.Lead, rt.TR, rt.Flash, rt.Top, rt.Zone,
+				rt.Height, rt.Pitches, rt.Setter, rt.Color, rt.MPID, verify, rt.Description,
+			})
+		}
+	}
+	return rows
+}
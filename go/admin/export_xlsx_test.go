@@ -0,0 +1,51 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestNumericRows(t *testing.T) {
+	got := numericRows([][]string{
+		{"team", "climber_1", "score", "climbs"},
+		{"Team A", "123", "45", ""},
+	}, 2)
+	want := [][]interface{}{
+		{"team", "climber_1", "score", "climbs"},
+		{"Team A", "123", 45, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("numericRows returned %v; want %v", got, want)
+	}
+}
+
+func TestMakeRouteAscentRows(t *testing.T) {
+	areas := []db.Area{
+		{ID: "a1", Name: "Area 1", Routes: []db.Route{{ID: "r1", Name: "Route 1", Grade: "5.10a"}}},
+		{ID: "a2", Name: "Area 2", Routes: []db.Route{
+			{ID: "b1", Name: "Boulder 1", Grade: "V2"},
+			{ID: "b2", Name: "Boulder 2", Grade: "V4"},
+		}},
+	}
+	users := []userSummary{
+		{Name: "User 1", Climbs: map[string]db.ClimbState{"r1": db.Lead, "b1": db.Flash}},
+		{Name: "User 2", Climbs: map[string]db.ClimbState{"r1": db.TopRope, "b1": db.Zone}},
+		{Name: "User 3", Climbs: map[string]db.ClimbState{"r1": db.Lead, "unknown": db.Lead}},
+	}
+	got := makeRouteAscentRows(users, areas)
+	want := [][]interface{}{
+		{"area", "route", "grade", "lead", "tr", "flash", "top", "zone", "ascents"},
+		{"Area 1", "Route 1", "5.10a", 2, 1, 0, 0, 0, 3},
+		{"Area 2", "Boulder 1", "V2", 0, 0, 1, 0, 1, 1},
+		{"Area 2", "Boulder 2", "V4", 0, 0, 0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("makeRouteAscentRows returned %v; want %v", got, want)
+	}
+}
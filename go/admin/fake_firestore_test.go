@@ -0,0 +1,383 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/api/option"
+	pb "google.golang.org/genproto/googleapis/firestore/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const fakeProject = "fake-project"
+
+// fakeFirestore is an in-memory implementation of the parts of the Cloud Firestore
+// gRPC API used by getScores, handleEmptyTeams, findClimbs, handleArchive,
+// getStandings, and eraseUserDocs. It's used for tests and benchmarks.
+// Transactions are serialized.
+type fakeFirestore struct {
+	pb.FirestoreServer // unimplemented methods panic
+
+	// latency is added to each RPC to approximate a network round trip.
+	latency time.Duration
+	// beforeCommit is called (if non-nil) at the start of each Commit call.
+	beforeCommit func()
+
+	txMu sync.Mutex // held while a transaction is in progress
+
+	mu      sync.Mutex
+	docs    map[string]*pb.Document // keyed by full resource name
+	queries map[string]int          // number of queries keyed by collection ID
+	txs     map[string]bool         // IDs of in-progress transactions
+	nextTx  int                     // used to generate transaction IDs
+}
+
+// newFakeFirestore starts a fakeFirestore and returns a client connected to it.
+// The server is stopped when the test finishes.
+func newFakeFirestore(tb testing.TB, latency time.Duration) (*fakeFirestore, *firestore.Client) {
+	ff := &fakeFirestore{
+		latency: latency,
+		docs:    make(map[string]*pb.Document),
+		queries: make(map[string]int),
+		txs:     make(map[string]bool),
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterFirestoreServer(srv, ff)
+	go srv.Serve(lis)
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) { return lis.Dial() }))
+	if err != nil {
+		tb.Fatal("Failed dialing fake server: ", err)
+	}
+	client, err := firestore.NewClient(ctx, fakeProject, option.WithGRPCConn(conn))
+	if err != nil {
+		tb.Fatal("Failed creating client: ", err)
+	}
+	tb.Cleanup(func() {
+		client.Close()
+		srv.Stop()
+	})
+	return ff, client
+}
+
+// root returns the resource name under which docs are stored.
+func (ff *fakeFirestore) root() string {
+	return "projects/" + fakeProject + "/databases/(default)/documents"
+}
+
+// set stores data (consisting of maps, slices, strings, ints, and bools) at path,
+// e.g. "teams/abc".
+func (ff *fakeFirestore) set(path string, data map[string]interface{}) {
+	now := ptypes.TimestampNow()
+	name := ff.root() + "/" + path
+	ff.mu.Lock()
+	if old, ok := ff.docs[name]; ok && !(now.Seconds > old.UpdateTime.Seconds ||
+		(now.Seconds == old.UpdateTime.Seconds && now.Nanos > old.UpdateTime.Nanos)) {
+		// Make sure that the update time changes.
+		now = &tspb.Timestamp{Seconds: old.UpdateTime.Seconds, Nanos: old.UpdateTime.Nanos + 1}
+	}
+	ff.docs[name] = &pb.Document{Name: name, Fields: toFakeFields(data), CreateTime: now, UpdateTime: now}
+	ff.mu.Unlock()
+}
+
+// count returns the number of docs directly within the collection at path.
+func (ff *fakeFirestore) count(path string) int {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	return len(ff.list(ff.root() + "/" + path))
+}
+
+// list returns the docs directly within the collection named coll, sorted by name.
+// ff.mu must be held.
+func (ff *fakeFirestore) list(coll string) []*pb.Document {
+	var docs []*pb.Document
+	for name, doc := range ff.docs {
+		if strings.HasPrefix(name, coll+"/") && !strings.Contains(name[len(coll)+1:], "/") {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+func (ff *fakeFirestore) BatchGetDocuments(req *pb.BatchGetDocumentsRequest, stream pb.Firestore_BatchGetDocumentsServer) error {
+	time.Sleep(ff.latency)
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	now := ptypes.TimestampNow()
+	for _, name := range req.Documents {
+		res := &pb.BatchGetDocumentsResponse{ReadTime: now}
+		if doc, ok := ff.docs[name]; ok {
+			res.Result = &pb.BatchGetDocumentsResponse_Found{Found: doc}
+		} else {
+			res.Result = &pb.BatchGetDocumentsResponse_Missing{Missing: name}
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ff *fakeFirestore) RunQuery(req *pb.RunQueryRequest, stream pb.Firestore_RunQueryServer) error {
+	time.Sleep(ff.latency)
+	q := req.GetStructuredQuery()
+	if len(q.GetFrom()) != 1 {
+		return fmt.Errorf("unsupported query %v", q)
+	}
+	// Only a single equality filter is supported.
+	var filter *pb.StructuredQuery_FieldFilter
+	if q.Where != nil {
+		if filter = q.Where.GetFieldFilter(); filter == nil || filter.Op != pb.StructuredQuery_FieldFilter_EQUAL {
+			return fmt.Errorf("unsupported filter %v", q.Where)
+		}
+	}
+	ff.mu.Lock()
+	docs := ff.list(req.Parent + "/" + q.From[0].CollectionId)
+	ff.queries[q.From[0].CollectionId]++
+	ff.mu.Unlock()
+
+	now := ptypes.TimestampNow()
+	for _, doc := range docs {
+		if filter != nil && !proto.Equal(getFakeField(doc.Fields, filter.Field.FieldPath), filter.Value) {
+			continue
+		}
+		if err := stream.Send(&pb.RunQueryResponse{Document: doc, ReadTime: now}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryCount returns the number of queries that have been run against the
+// collection with the supplied ID.
+func (ff *fakeFirestore) queryCount(collID string) int {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	return ff.queries[collID]
+}
+
+func (ff *fakeFirestore) BeginTransaction(ctx context.Context, req *pb.BeginTransactionRequest) (
+	*pb.BeginTransactionResponse, error) {
+	time.Sleep(ff.latency)
+	ff.txMu.Lock()
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	ff.nextTx++
+	id := fmt.Sprintf("tx%d", ff.nextTx)
+	ff.txs[id] = true
+	return &pb.BeginTransactionResponse{Transaction: []byte(id)}, nil
+}
+
+func (ff *fakeFirestore) Rollback(ctx context.Context, req *pb.RollbackRequest) (*empty.Empty, error) {
+	time.Sleep(ff.latency)
+	ff.mu.Lock()
+	ff.endTransaction(req.Transaction)
+	ff.mu.Unlock()
+	return &empty.Empty{}, nil
+}
+
+// endTransaction ends the in-progress transaction with the supplied ID, if any.
+// ff.mu must be held.
+func (ff *fakeFirestore) endTransaction(id []byte) {
+	if ff.txs[string(id)] {
+		delete(ff.txs, string(id))
+		ff.txMu.Unlock()
+	}
+}
+
+func (ff *fakeFirestore) Commit(ctx context.Context, req *pb.CommitRequest) (*pb.CommitResponse, error) {
+	time.Sleep(ff.latency)
+	if ff.beforeCommit != nil {
+		ff.beforeCommit()
+	}
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	defer ff.endTransaction(req.Transaction)
+
+	// Check preconditions first, since commits are atomic.
+	for _, w := range req.Writes {
+		name := w.GetDelete()
+		if doc := w.GetUpdate(); doc != nil {
+			for _, fp := range w.GetUpdateMask().GetFieldPaths() {
+				if strings.Contains(fp, "`") {
+					return nil, fmt.Errorf("unsupported field path %q", fp)
+				}
+			}
+			name = doc.Name
+		} else if name == "" {
+			return nil, fmt.Errorf("unsupported write %v", w)
+		}
+		doc, ok := ff.docs[name]
+		switch cd := w.GetCurrentDocument(); {
+		case cd.GetUpdateTime() != nil && (!ok || !proto.Equal(doc.UpdateTime, cd.GetUpdateTime())):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v was modified", name)
+		case cd != nil && cd.GetUpdateTime() == nil && cd.GetExists() && !ok:
+			return nil, status.Errorf(codes.NotFound, "%v doesn't exist", name)
+		case cd != nil && cd.GetUpdateTime() == nil && !cd.GetExists() && ok:
+			return nil, status.Errorf(codes.AlreadyExists, "%v already exists", name)
+		}
+	}
+
+	now := ptypes.TimestampNow()
+	res := &pb.CommitResponse{CommitTime: now}
+	for _, w := range req.Writes {
+		if doc := w.GetUpdate(); doc != nil {
+			created, fields := now, doc.Fields
+			if old, ok := ff.docs[doc.Name]; ok {
+				created = old.CreateTime
+				if w.UpdateMask != nil {
+					// Only replace the fields in the mask. Copy the old doc since it
+					// may be in the process of being sent to a reader.
+					fields = proto.Clone(old).(*pb.Document).Fields
+					for _, fp := range w.UpdateMask.FieldPaths {
+						setFakeField(fields, fp, getFakeField(doc.Fields, fp))
+					}
+				}
+			}
+			ff.docs[doc.Name] = &pb.Document{Name: doc.Name, Fields: fields, CreateTime: created, UpdateTime: now}
+		} else {
+			delete(ff.docs, w.GetDelete())
+		}
+		res.WriteResults = append(res.WriteResults, &pb.WriteResult{UpdateTime: now})
+	}
+	return res, nil
+}
+
+// getFakeField returns the value at the dot-separated path within fields, or nil if
+// it doesn't exist. Quoted path components aren't supported.
+func getFakeField(fields map[string]*pb.Value, path string) *pb.Value {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		v, ok := fields[p]
+		if !ok {
+			return nil
+		} else if i == len(parts)-1 {
+			return v
+		}
+		fields = v.GetMapValue().GetFields()
+	}
+	return nil
+}
+
+// setFakeField sets the value at the dot-separated path within fields to v,
+// creating intermediate maps as needed. The value is deleted if v is nil.
+// Quoted path components aren't supported.
+func setFakeField(fields map[string]*pb.Value, path string, v *pb.Value) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		mv := fields[p].GetMapValue()
+		if mv == nil {
+			if v == nil {
+				return
+			}
+			mv = &pb.MapValue{Fields: make(map[string]*pb.Value)}
+			fields[p] = &pb.Value{ValueType: &pb.Value_MapValue{MapValue: mv}}
+		}
+		if mv.Fields == nil {
+			mv.Fields = make(map[string]*pb.Value)
+		}
+		fields = mv.Fields
+	}
+	if last := parts[len(parts)-1]; v == nil {
+		delete(fields, last)
+	} else {
+		fields[last] = v
+	}
+}
+
+// toFakeFields converts data to Firestore document fields.
+func toFakeFields(data map[string]interface{}) map[string]*pb.Value {
+	fields := make(map[string]*pb.Value, len(data))
+	for k, v := range data {
+		fields[k] = toFakeValue(v)
+	}
+	return fields
+}
+
+// toFakeValue converts v to a Firestore value.
+func toFakeValue(v interface{}) *pb.Value {
+	switch tv := v.(type) {
+	case string:
+		return &pb.Value{ValueType: &pb.Value_StringValue{StringValue: tv}}
+	case int:
+		return &pb.Value{ValueType: &pb.Value_IntegerValue{IntegerValue: int64(tv)}}
+	case bool:
+		return &pb.Value{ValueType: &pb.Value_BooleanValue{BooleanValue: tv}}
+	case []interface{}:
+		vals := make([]*pb.Value, len(tv))
+		for i, e := range tv {
+			vals[i] = toFakeValue(e)
+		}
+		return &pb.Value{ValueType: &pb.Value_ArrayValue{ArrayValue: &pb.ArrayValue{Values: vals}}}
+	case map[string]interface{}:
+		return &pb.Value{ValueType: &pb.Value_MapValue{MapValue: &pb.MapValue{Fields: toFakeFields(tv)}}}
+	default:
+		panic(fmt.Sprintf("unsupported type %T", v))
+	}
+}
+
+// addFakeCompetition adds route data and numTeams teams (each with two members
+// who have climbed some of numRoutes routes) to ff. Every emptyEvery-th team is
+// empty if emptyEvery is positive.
+func addFakeCompetition(ff *fakeFirestore, numTeams, numRoutes, emptyEvery int) {
+	indexedRoutes := make(map[string]interface{})
+	var sortedRoutes []interface{}
+	for i := 0; i < numRoutes; i++ {
+		id := fmt.Sprintf("r%d", i)
+		rt := map[string]interface{}{"name": "Route " + id, "lead": 10 + i, "tr": 5 + i, "height": 50}
+		indexedRoutes[id] = rt
+		sortedRoutes = append(sortedRoutes, map[string]interface{}{
+			"id": id, "name": "Route " + id, "lead": 10 + i, "tr": 5 + i, "height": 50,
+		})
+	}
+	ff.set("global/indexedData", map[string]interface{}{
+		"areas":  map[string]interface{}{"a": map[string]interface{}{"name": "Area"}},
+		"routes": indexedRoutes,
+	})
+	ff.set("global/sortedData", map[string]interface{}{
+		"areas": []interface{}{map[string]interface{}{"id": "a", "name": "Area", "routes": sortedRoutes}},
+	})
+
+	for i := 0; i < numTeams; i++ {
+		users := make(map[string]interface{})
+		if emptyEvery <= 0 || i%emptyEvery != 0 {
+			for j := 0; j < 2; j++ {
+				climbs := make(map[string]interface{})
+				for k := (i + j) % 3; k < numRoutes; k += 3 {
+					climbs[fmt.Sprintf("r%d", k)] = 1 + k%2
+				}
+				users[fmt.Sprintf("u%d-%d", i, j)] = map[string]interface{}{
+					"name": fmt.Sprintf("User %d-%d", i, j), "climbs": climbs,
+				}
+			}
+		}
+		ff.set(fmt.Sprintf("teams/t%05d", i), map[string]interface{}{
+			"name":   fmt.Sprintf("Team %d", i),
+			"invite": fmt.Sprintf("%06d", i),
+			"users":  users,
+		})
+		ff.set(fmt.Sprintf("invites/%06d", i), map[string]interface{}{"team": fmt.Sprintf("t%05d", i)})
+	}
+}
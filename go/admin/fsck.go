@@ -0,0 +1,312 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// maxBatchWrites is the maximum number of writes in a single Firestore batch.
+const maxBatchWrites = 500
+
+// handleFsck handles an "fsck" POST request.
+// It checks that competition comp's users, teams, and invites reference each other
+// consistently and reports any problems. If the "fsckRepair" parameter is set to "1",
+// problems that can be fixed automatically are repaired.
+func handleFsck(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	users := make(map[string]db.User)
+	if err := loadCollection(ctx, client.Collection(db.UserCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var u db.User
+		err := snap.DataTo(&u)
+		users[snap.Ref.ID] = u
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading users: %v", err), http.StatusInternalServerError)
+		return
+	}
+	teams := make(map[string]db.Team)
+	if err := loadCollection(ctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var t db.Team
+		err := snap.DataTo(&t)
+		teams[snap.Ref.ID] = t
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	invites := make(map[string]db.Invite)
+	if err := loadCollection(ctx, client.Collection(db.InviteCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var inv db.Invite
+		err := snap.DataTo(&inv)
+		invites[snap.Ref.ID] = inv
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading invites: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	probs := checkConsistency(users, teams, invites, cfg.GetTeamSize(), loc)
+	repair := r.FormValue("fsckRepair") == "1"
+	var fixes []fsckFix
+	for _, p := range probs {
+		fixes = append(fixes, p.fixes...)
+	}
+
+	if repair && len(fixes) > 0 {
+		colls := map[fsckColl]*firestore.CollectionRef{
+			userColl:   client.Collection(db.UserCollectionPath(comp)),
+			teamColl:   client.Collection(db.TeamCollectionPath(comp)),
+			inviteColl: client.Collection(db.InviteCollectionPath(comp)),
+		}
+		for start := 0; start < len(fixes); start += maxBatchWrites {
+			end := start + maxBatchWrites
+			if end > len(fixes) {
+				end = len(fixes)
+			}
+			batch := client.Batch()
+			for _, f := range fixes[start:end] {
+				ref := colls[f.coll].Doc(f.id)
+				log.Printf("Fixing %v: %+v", ref.Path, f)
+				switch {
+				case f.set != nil:
+					batch.Set(ref, f.set)
+				case f.updates != nil:
+					batch.Update(ref, f.updates)
+				default:
+					batch.Delete(ref)
+				}
+			}
+			if _, err := batch.Commit(ctx); err != nil {
+				http.Error(w, loc.T("Failed committing fixes: %v", err), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	fmt.Fprintln(w, loc.T("Checked %d user(s), %d team(s), and %d invite(s); found %d problem(s)",
+		len(users), len(teams), len(invites), len(probs)))
+	for _, p := range probs {
+		switch {
+		case len(p.fixes) == 0:
+			fmt.Fprintln(w, loc.T("%v (must be fixed manually)", p.desc))
+		case repair:
+			fmt.Fprintln(w, loc.T("%v (fixed)", p.desc))
+		default:
+			fmt.Fprintln(w, p.desc)
+		}
+	}
+}
+
+// loadCollection calls f for each document in coll.
+func loadCollection(ctx context.Context, coll *firestore.CollectionRef, f func(*firestore.DocumentSnapshot) error) error {
+	it := coll.Documents(ctx)
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := f(snap); err != nil {
+			return fmt.Errorf("%v: %v", snap.Ref.Path, err)
+		}
+	}
+}
+
+// fsckColl identifies a collection within a competition.
+type fsckColl int
+
+const (
+	userColl fsckColl = iota
+	teamColl
+	inviteColl
+)
+
+// fsckFix describes a write that fixes a problem found by checkConsistency.
+type fsckFix struct {
+	coll    fsckColl
+	id      string             // doc ID within coll
+	set     interface{}        // if non-nil, doc is replaced with this
+	updates []firestore.Update // if non-nil, doc is updated with these
+	// If both set and updates are nil, the doc is deleted.
+}
+
+// fsckProblem describes an inconsistency found by checkConsistency.
+type fsckProblem struct {
+	desc  string    // human-readable description
+	fixes []fsckFix // writes that fix the problem; empty if it can't be fixed automatically
+}
+
+// checkConsistency checks the relationships between users, teams, and invites (each
+// keyed by doc ID) and returns all problems that were found in a deterministic order.
+// teamSize is the maximum number of members on a team. Problems are described using loc.
+func checkConsistency(users map[string]db.User, teams map[string]db.Team,
+	invites map[string]db.Invite, teamSize int, loc *localizer) []fsckProblem {
+	var probs []fsckProblem
+	add := func(desc string, fixes ...fsckFix) {
+		probs = append(probs, fsckProblem{desc, fixes})
+	}
+
+	// Visit docs in a consistent order so that problems are reported deterministically.
+	tids := make([]string, 0, len(teams))
+	for tid := range teams {
+		tids = append(tids, tid)
+	}
+	sort.Strings(tids)
+	codes := make([]string, 0, len(invites))
+	for code := range invites {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	uids := make([]string, 0, len(users))
+	for uid := range users {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	// Determine the teams that actively list each user.
+	memberOf := make(map[string][]string)
+	for _, tid := range tids {
+		for uid, u := range teams[tid].Users {
+			if !u.Left {
+				memberOf[uid] = append(memberOf[uid], tid)
+			}
+		}
+	}
+
+	for _, tid := range tids {
+		team := teams[tid]
+		// Members who left still count toward the size, as in handleMergeTeams,
+		// handleMoveUser, and the web app's security rules.
+		if n := len(team.Users); n > teamSize {
+			add(loc.T("Team %v (%q) has %d members", tid, team.Name, n))
+		}
+		members := make([]string, 0, len(team.Users))
+		for uid := range team.Users {
+			members = append(members, uid)
+		}
+		sort.Strings(members)
+		for _, uid := range members {
+			if _, ok := users[uid]; !ok && !team.Users[uid].Left {
+				add(loc.T("Team %v (%q) lists nonexistent user %v", tid, team.Name, uid),
+					removeTeamUser(tid, uid, team.Users[uid]))
+			}
+		}
+
+		if team.Invite == "" {
+			add(loc.T("Team %v (%q) has no invite code", tid, team.Name))
+		} else if inv, ok := invites[team.Invite]; !ok {
+			add(loc.T("Team %v (%q) has nonexistent invite %v", tid, team.Name, team.Invite),
+				fsckFix{coll: inviteColl, id: team.Invite, set: db.Invite{Team: tid}})
+		} else if inv.Team != tid {
+			if owner, ok := teams[inv.Team]; ok && owner.Invite == team.Invite {
+				add(loc.T("Team %v (%q) has invite %v belonging to team %v", tid, team.Name,
+					team.Invite, inv.Team))
+			} else {
+				add(loc.T("Team %v (%q) has invite %v pointing at team %v", tid, team.Name,
+					team.Invite, inv.Team),
+					fsckFix{coll: inviteColl, id: team.Invite, set: db.Invite{Team: tid}})
+			}
+		}
+	}
+
+	for _, code := range codes {
+		inv := invites[code]
+		if owner, ok := teams[inv.Team]; ok && owner.Invite == code {
+			continue // valid
+		}
+		// Skip invites that are claimed by other teams, since they were handled above.
+		claimed := false
+		for _, team := range teams {
+			if team.Invite == code {
+				claimed = true
+				break
+			}
+		}
+		if claimed {
+			continue
+		}
+		if _, ok := teams[inv.Team]; !ok {
+			add(loc.T("Invite %v points at nonexistent team %v", code, inv.Team),
+				fsckFix{coll: inviteColl, id: code})
+		} else {
+			add(loc.T("Invite %v points at team %v, which has invite %v", code, inv.Team,
+				teams[inv.Team].Invite), fsckFix{coll: inviteColl, id: code})
+		}
+	}
+
+	for _, uid := range uids {
+		user := users[uid]
+		tids := memberOf[uid]
+
+		// Choose the team that the user should be on.
+		want := ""
+		for _, tid := range tids {
+			if tid == user.Team {
+				want = tid
+				break
+			}
+		}
+		if want == "" && len(tids) > 0 {
+			want = tids[0]
+		}
+
+		if user.Team != want {
+			var fix fsckFix
+			if want == "" {
+				fix = fsckFix{coll: userColl, id: uid, updates: []firestore.Update{
+					{Path: "team", Value: firestore.Delete}}}
+			} else {
+				fix = fsckFix{coll: userColl, id: uid, updates: []firestore.Update{
+					{Path: "team", Value: want}}}
+			}
+			if user.Team == "" {
+				add(loc.T("User %v (%q) is listed on team %v but doesn't reference it",
+					uid, user.Name, want), fix)
+			} else if _, ok := teams[user.Team]; !ok {
+				add(loc.T("User %v (%q) references nonexistent team %v", uid, user.Name, user.Team), fix)
+			} else {
+				add(loc.T("User %v (%q) references team %v, which doesn't list them as a member",
+					uid, user.Name, user.Team), fix)
+			}
+		}
+		for _, tid := range tids {
+			if tid != want {
+				add(loc.T("User %v (%q) is also listed on team %v", uid, user.Name, tid),
+					removeTeamUser(tid, uid, teams[tid].Users[uid]))
+			}
+		}
+	}
+
+	return probs
+}
+
+// removeTeamUser returns a fix that removes user uid (with entry u) from team tid.
+// As when a user leaves a team in the web app, users who have reported climbs are
+// marked as having left so their climbs are still counted in the team's score.
+func removeTeamUser(tid, uid string, u db.TeamUser) fsckFix {
+	if len(u.Climbs) > 0 {
+		return fsckFix{coll: teamColl, id: tid, updates: []firestore.Update{
+			{FieldPath: firestore.FieldPath{"users", uid, "left"}, Value: true}}}
+	}
+	return fsckFix{coll: teamColl, id: tid, updates: []firestore.Update{
+		{FieldPath: firestore.FieldPath{"users", uid}, Value: firestore.Delete}}}
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	climbs := map[string]db.ClimbState{"r1": db.Lead}
+	del := func(path string) []firestore.Update {
+		return []firestore.Update{{Path: path, Value: firestore.Delete}}
+	}
+	delUser := func(uid string) []firestore.Update {
+		return []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: firestore.Delete}}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		users   map[string]db.User
+		teams   map[string]db.Team
+		invites map[string]db.Invite
+		want    []fsckProblem
+	}{
+		{
+			desc: "consistent",
+			users: map[string]db.User{
+				"u1": {Name: "A", Team: "t1"},
+				"u2": {Name: "B", Team: "t1"},
+				"u3": {Name: "C"},
+				"u4": {Name: "D", Team: "t2"},
+			},
+			teams: map[string]db.Team{
+				"t1": {Name: "T1", Invite: "111111", Users: map[string]db.TeamUser{
+					"u1": {Name: "A"}, "u2": {Name: "B"}}},
+				"t2": {Name: "T2", Invite: "222222", Users: map[string]db.TeamUser{
+					"u3": {Name: "C", Climbs: climbs, Left: true}, "u4": {Name: "D"}}},
+			},
+			invites: map[string]db.Invite{"111111": {Team: "t1"}, "222222": {Team: "t2"}},
+		},
+		{
+			desc: "dangling refs",
+			users: map[string]db.User{
+				"u1": {Name: "A", Team: "gone"},
+				"u2": {Name: "B"},
+			},
+			teams: map[string]db.Team{
+				"t1": {Name: "T1", Invite: "111111", Users: map[string]db.TeamUser{
+					"u2": {Name: "B"}, "u3": {Name: "C"}}},
+			},
+			invites: map[string]db.Invite{"111111": {Team: "t1"}, "999999": {Team: "gone"}},
+			want: []fsckProblem{
+				{`Team t1 ("T1") lists nonexistent user u3`,
+					[]fsckFix{{coll: teamColl, id: "t1", updates: delUser("u3")}}},
+				{"Invite 999999 points at nonexistent team gone",
+					[]fsckFix{{coll: inviteColl, id: "999999"}}},
+				{`User u1 ("A") references nonexistent team gone`,
+					[]fsckFix{{coll: userColl, id: "u1", updates: del("team")}}},
+				{`User u2 ("B") is listed on team t1 but doesn't reference it`,
+					[]fsckFix{{coll: userColl, id: "u2", updates: []firestore.Update{
+						{Path: "team", Value: "t1"}}}}},
+			},
+		},
+		{
+			desc:  "user on two teams",
+			users: map[string]db.User{"u1": {Name: "A", Team: "t2"}},
+			teams: map[string]db.Team{
+				"t1": {Name: "T1", Invite: "111111", Users: map[string]db.TeamUser{
+					"u1": {Name: "A", Climbs: climbs}}},
+				"t2": {Name: "T2", Invite: "222222", Users: map[string]db.TeamUser{
+					"u1": {Name: "A"}}},
+			},
+			invites: map[string]db.Invite{"111111": {Team: "t1"}, "222222": {Team: "t2"}},
+			want: []fsckProblem{
+				{`User u1 ("A") is also listed on team t1`,
+					[]fsckFix{{coll: teamColl, id: "t1", updates: []firestore.Update{
+						{FieldPath: firestore.FieldPath{"users", "u1", "left"}, Value: true}}}}},
+			},
+		},
+		{
+			desc: "invite problems and oversized team",
+			users: map[string]db.User{
+				"u1": {Name: "A", Team: "t1"},
+				"u2": {Name: "B", Team: "t1"},
+				"u3": {Name: "C"},
+			},
+			teams: map[string]db.Team{
+				// Members who left count toward the team size.
+				"t1": {Name: "T1", Invite: "111111", Users: map[string]db.TeamUser{
+					"u1": {Name: "A"}, "u2": {Name: "B"}, "u3": {Name: "C", Climbs: climbs, Left: true}}},
+				"t2": {Name: "T2", Invite: "111111"},
+				"t3": {Name: "T3", Invite: "333333"},
+				"t4": {Name: "T4"},
+			},
+			invites: map[string]db.Invite{"111111": {Team: "t1"}, "444444": {Team: "t1"}},
+			want: []fsckProblem{
+				{`Team t1 ("T1") has 3 members`, nil},
+				{`Team t2 ("T2") has invite 111111 belonging to team t1`, nil},
+				{`Team t3 ("T3") has nonexistent invite 333333`,
+					[]fsckFix{{coll: inviteColl, id: "333333", set: db.Invite{Team: "t3"}}}},
+				{`Team t4 ("T4") has no invite code`, nil},
+				{"Invite 444444 points at team t1, which has invite 111111",
+					[]fsckFix{{coll: inviteColl, id: "444444"}}},
+			},
+		},
+	} {
+		if got := checkConsistency(tc.users, tc.teams, tc.invites, db.DefaultTeamSize, defaultLocalizer); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: checkConsistency returned:\n%+v\nwant:\n%+v", tc.desc, got, tc.want)
+		}
+	}
+}
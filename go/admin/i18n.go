@@ -0,0 +1,150 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultLang  = "en"     // language used when no supported language is requested
+	feetPerMetre = 3.280839 // used when displaying heights in metres
+)
+
+// langs describes the supported languages. Their translations are in catalogs.
+var langs = []struct{ code, name string }{
+	{"en", "English"},
+	{"es", "Español"},
+}
+
+// catalogs contains translations of English messages for each supported language
+// other than English, keyed by language code.
+var catalogs = map[string]map[string]string{
+	"es": esMessages,
+}
+
+// localizer translates messages and formats heights for an HTTP request.
+// It's used for the admin page, the results and error messages of admin operations,
+// and documents that may be shared with climbers: scoreboards, scorecards, statistics,
+// and awards. Details of unexpected errors (e.g. from Firestore) aren't translated.
+type localizer struct {
+	lang   string // language code from langs
+	metres bool   // display heights in metres rather than feet
+}
+
+// defaultLocalizer uses English and feet.
+var defaultLocalizer = &localizer{lang: defaultLang}
+
+// newLocalizer returns a localizer for r. The language is taken from the "lang"
+// parameter if it's supported or from the Accept-Language header otherwise.
+// Heights are displayed in metres if the "units" parameter is "m".
+func newLocalizer(r *http.Request) *localizer {
+	loc := localizer{lang: defaultLang, metres: r.FormValue("units") == "m"}
+	if lang := r.FormValue("lang"); supportedLang(lang) {
+		loc.lang = lang
+	} else {
+		for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+			base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+			if supportedLang(base) {
+				loc.lang = base
+				break
+			}
+		}
+	}
+	return &loc
+}
+
+// supportedLang returns true if code appears in langs.
+func supportedLang(code string) bool {
+	for _, l := range langs {
+		if l.code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAcceptLanguage returns the language tags from an Accept-Language header value
+// in order of decreasing preference. Tags with zero quality are omitted.
+func parseAcceptLanguage(h string) []string {
+	type weightedTag struct {
+		tag string
+		q   float64
+	}
+	var wts []weightedTag
+	for _, part := range strings.Split(h, ",") {
+		fields := strings.Split(part, ";")
+		wt := weightedTag{tag: strings.TrimSpace(fields[0]), q: 1}
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if q, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					wt.q = q
+				}
+			}
+		}
+		if wt.tag != "" && wt.q > 0 {
+			wts = append(wts, wt)
+		}
+	}
+	sort.SliceStable(wts, func(i, j int) bool { return wts[i].q > wts[j].q })
+	tags := make([]string, len(wts))
+	for i, wt := range wts {
+		tags[i] = wt.tag
+	}
+	return tags
+}
+
+// T translates the English message msg and formats it with args using fmt.Sprintf.
+// msg is returned untranslated if the localizer's language lacks a translation.
+func (l *localizer) T(msg string, args ...interface{}) string {
+	if tr, ok := catalogs[l.lang][msg]; ok {
+		msg = tr
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// height formats a height in feet for display, e.g. "60'" or "18 m".
+func (l *localizer) height(feet int) string {
+	if l.metres {
+		return fmt.Sprintf("%d m", int(math.Round(float64(feet)/feetPerMetre)))
+	}
+	return fmt.Sprintf("%d'", feet)
+}
+
+// ordinal formats n as an ordinal number, e.g. "1st" or "1.º".
+func (l *localizer) ordinal(n int) string {
+	switch l.lang {
+	case "es":
+		return strconv.Itoa(n) + ".º"
+	default:
+		return ordinal(n)
+	}
+}
+
+// funcs returns template functions that use l.
+// Messages passed to "T" are trusted and may contain HTML markup, but arguments are escaped.
+func (l *localizer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"T": func(msg string, args ...interface{}) template.HTML {
+			for i, arg := range args {
+				if s, ok := arg.(string); ok {
+					args[i] = template.HTMLEscapeString(s)
+				}
+			}
+			return template.HTML(l.T(msg, args...))
+		},
+		"height": l.height,
+	}
+}
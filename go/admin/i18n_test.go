@@ -0,0 +1,157 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		h    string
+		want []string
+	}{
+		{"", []string{}},
+		{"es", []string{"es"}},
+		{"en-US,en;q=0.9", []string{"en-US", "en"}},
+		{"fr;q=0.5, es-PR;q=0.8, de", []string{"de", "es-PR", "fr"}},
+		{"es;q=0, en;q=bad", []string{"en"}},
+	} {
+		if got := parseAcceptLanguage(tc.h); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseAcceptLanguage(%q) = %q; want %q", tc.h, got, tc.want)
+		}
+	}
+}
+
+func TestNewLocalizer(t *testing.T) {
+	for _, tc := range []struct {
+		query, accept string
+		want          localizer
+	}{
+		{"", "", localizer{lang: "en"}},
+		{"", "es-PR,en;q=0.8", localizer{lang: "es"}},
+		{"", "fr,es;q=0.5", localizer{lang: "es"}},
+		{"lang=en", "es", localizer{lang: "en"}},
+		{"lang=es&units=m", "", localizer{lang: "es", metres: true}},
+		{"lang=fr&units=ft", "", localizer{lang: "en"}},
+	} {
+		req := httptest.NewRequest("GET", "/?"+tc.query, nil)
+		if tc.accept != "" {
+			req.Header.Set("Accept-Language", tc.accept)
+		}
+		if got := newLocalizer(req); *got != tc.want {
+			t.Errorf("newLocalizer(%q, %q) = %+v; want %+v", tc.query, tc.accept, *got, tc.want)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	en := &localizer{lang: "en"}
+	es := &localizer{lang: "es", metres: true}
+	for _, tc := range []struct {
+		got, want string
+	}{
+		{en.T("Score"), "Score"},
+		{es.T("Score"), "Puntuación"},
+		{es.T("Bad view %q", "x"), `Vista inválida "x"`},
+		{es.T("Untranslated %d", 3), "Untranslated 3"},
+		{en.height(60), "60'"},
+		{es.height(60), "18 m"},
+		{es.height(0), "0 m"},
+		{en.ordinal(2), "2nd"},
+		{es.ordinal(2), "2.º"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("Got %q; want %q", tc.got, tc.want)
+		}
+	}
+}
+
+// templateMsgRegexp matches messages passed to the "T" template function.
+var templateMsgRegexp = regexp.MustCompile(`\{\{T ("(?:[^"\\]|\\.)*")`)
+
+// codeMsgRegexp matches messages passed to localizer.T in Go code.
+var codeMsgRegexp = regexp.MustCompile(`\bloc\.T\(("(?:[^"\\]|\\.)*")`)
+
+func TestCatalogs(t *testing.T) {
+	// Check that all messages in templates are translated.
+	var msgs []string
+	addMsgs := func(re *regexp.Regexp, text string) {
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			msg, err := strconv.Unquote(m[1])
+			if err != nil {
+				t.Fatalf("Failed unquoting %v: %v", m[1], err)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	for _, text := range []string{adminTemplate, scoresTemplate, scorecardTemplate,
+		routeStatsTemplate, areaStatsTemplate} {
+		addMsgs(templateMsgRegexp, text)
+	}
+	// Also check messages that are translated in code.
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addMsgs(codeMsgRegexp, string(b))
+	}
+	for _, s := range climbStateLabels {
+		msgs = append(msgs, s)
+	}
+	msgs = append(msgs, uncategorized, allClimbers)
+	for lang, cat := range catalogs {
+		for _, msg := range msgs {
+			if _, ok := cat[msg]; !ok {
+				t.Errorf("%v catalog lacks %q", lang, msg)
+			}
+		}
+		// Check that translations have the same formatting verbs as the original messages.
+		verbs := regexp.MustCompile(`%[a-z]`)
+		for msg, tr := range cat {
+			if a, b := verbs.FindAllString(msg, -1), verbs.FindAllString(tr, -1); !reflect.DeepEqual(a, b) {
+				t.Errorf("%v translation %q of %q has verbs %q; want %q", lang, tr, msg, b, a)
+			}
+		}
+	}
+}
+
+func TestWriteAdminPage(t *testing.T) {
+	for _, tc := range []struct {
+		loc  *localizer
+		want []string
+	}{
+		{&localizer{lang: "en"}, []string{"<title>Admin</title>", `<option value="en" selected>`,
+			"<code>Scoreboard</code> Cloud Function", `placeholder="Type &#39;REALLY CLEAR SCORES&#39;"`}},
+		{&localizer{lang: "es", metres: true}, []string{"<title>Administración</title>",
+			`<option value="es" selected>`, `<option value="m" selected>`, "Contraseña"}},
+	} {
+		var b bytes.Buffer
+		if err := writeAdminPage(&b, tc.loc); err != nil {
+			t.Fatalf("writeAdminPage(%+v) failed: %v", *tc.loc, err)
+		}
+		for _, s := range tc.want {
+			if !strings.Contains(b.String(), s) {
+				t.Errorf("writeAdminPage(%+v) output doesn't contain %q", *tc.loc, s)
+			}
+		}
+	}
+}
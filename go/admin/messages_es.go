@@ -0,0 +1,341 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+// esMessages contains Spanish translations of English messages.
+// Climbing terms match the web app's es-PR locale (src/locale/es-PR.ts).
+var esMessages = map[string]string{
+	// Admin page.
+	"A password must be supplied to perform any admin operations.": "Se requiere una contraseña para realizar cualquier operación de administración.",
+	"Admin": "Administración",
+	"Allow the competition's scoreboard to be viewed without a password via the <code>Scoreboard</code> Cloud Function, e.g. on a big screen. Climbers' names can be abbreviated to their initials.": "Permitir ver la tabla de puntuaciones de la competencia sin contraseña mediante la Cloud Function <code>Scoreboard</code>, por ejemplo en una pantalla grande. Los nombres de los escaladores se pueden abreviar a sus iniciales.",
+	"Also delete all teams":                 "Borrar también todos los equipos",
+	"Also fill in empty fields":             "Completar también los campos vacíos",
+	"Also repair problems where possible":   "Reparar también los problemas cuando sea posible",
+	"Approve":                               "Aprobar",
+	"Archive":                               "Archivo",
+	"Archive competition":                   "Archivar competencia",
+	"Areas":                                 "Áreas",
+	"Areas (CSV)":                           "Áreas (CSV)",
+	"Areas CSV":                             "CSV de áreas",
+	"Attempts":                              "Intentos",
+	"Awards":                                "Premios",
+	"Awards (PDF)":                          "Premios (PDF)",
+	"Change climb":                          "Cambiar escalada",
+	"Check":                                 "Revisar",
+	"Check routes":                          "Revisar rutas",
+	"Check routes against Mountain Project": "Revisar rutas con Mountain Project",
+	"Check teams, users, and invites":       "Revisar equipos, usuarios e invitaciones",
+	"Clear scores":                          "Borrar puntuaciones",
+	"Clear scores for all teams and users.": "Borrar las puntuaciones de todos los equipos y usuarios.",
+	"Climbs of routes with the <code>verify</code> column set only count after they're approved. Enter the user and route above to approve or reject a climb.": "Las escaladas de rutas con la columna <code>verify</code> solo cuentan después de ser aprobadas. Ingrese el usuario y la ruta arriba para aprobar o rechazar una escalada.",
+	"Competition":    "Competencia",
+	"Confirm":        "Confirmar",
+	"Correct climbs": "Corregir escaladas",
+	"Delete all teams that don't have any members.": "Borrar todos los equipos que no tienen miembros.",
+	"Delete empty teams":                            "Borrar equipos vacíos",
+	"Download a PDF with the top teams and the top climbers in each category, followed by a certificate for each listed climber. Tied climbers share a place. Use 0 places to list everyone.": "Descargar un PDF con los mejores equipos y los mejores escaladores de cada categoría, seguido de un certificado para cada escalador listado. Los escaladores empatados comparten lugar. Use 0 lugares para listar a todos.",
+	"Download the existing area and route data in the same CSV format.": "Descargar los datos existentes de áreas y rutas en el mismo formato CSV.",
+	"Erase":  "Borrar",
+	"Export": "Exportar",
+	"Export or erase all data held about a user, identified by user ID or email address. Erasing deletes the user's account, so erase them from any other competitions (by user ID) first. Client log entries can be exported but not erased. Archives don't record user IDs, so erased users' names and climbs remain in them. Teams whose only member is erased are deleted along with their invite codes.": "Exportar o borrar todos los datos guardados sobre un usuario, identificado por ID de usuario o correo electrónico. Borrar elimina la cuenta del usuario, así que bórrelo primero de cualquier otra competencia (por ID de usuario). Las entradas del registro del cliente se pueden exportar pero no borrar. Los archivos no guardan los ID de usuario, así que los nombres y las escaladas de los usuarios borrados permanecen en ellos. Los equipos cuyo único miembro se borra se eliminan junto con sus códigos de invitación.",
+	"Feet":                        "Pies",
+	"Give team a new invite code": "Darle al equipo un código de invitación nuevo",
+	"Language":                    "Idioma",
+	"Leave the competition empty to use the default competition that is displayed by the web app.": "Deje la competencia vacía para usar la competencia predeterminada que muestra la aplicación web.",
+	"List archives":                     "Listar archivos",
+	"List pending":                      "Listar pendientes",
+	"List teams":                        "Listar equipos",
+	"Lock or unlock database":           "Bloquear o desbloquear base de datos",
+	"MP CSV":                            "CSV de MP",
+	"Manage teams":                      "Administrar equipos",
+	"Merge":                             "Unir",
+	"Metres":                            "Metros",
+	"Move other team's members to team": "Mover los miembros del otro equipo al equipo",
+	"Move user":                         "Mover usuario",
+	"Move user from team to new team with name": "Mover al usuario del equipo a un equipo nuevo con el nombre",
+	"Move user to team":                         "Mover al usuario al equipo",
+	"Name":                                      "Nombre",
+	"New invite code":                           "Nuevo código de invitación",
+	"Not climbed":                               "No escalado",
+	"Number of attempts for a top or zone":      "Número de intentos para un top o una zona",
+	"Old team":                                  "Equipo anterior",
+	"Other team":                                "Otro equipo",
+	"Password":                                  "Contraseña",
+	"Places":                                    "Lugares",
+	"Privacy requests":                          "Solicitudes de privacidad",
+	"Public":                                    "Pública",
+	"Public scoreboard":                         "Tabla de puntuaciones pública",
+	"Read-only":                                 "Solo lectura",
+	"Reason":                                    "Razón",
+	"Register":                                  "Registrar",
+	"Register participants":                     "Registrar participantes",
+	"Reject":                                    "Rechazar",
+	"Rename":                                    "Renombrar",
+	"Rename team to name":                       "Renombrar el equipo con el nombre",
+	"Repeat user to erase":                      "Repita el usuario para borrar",
+	"Report users, teams, and invite codes that don't reference each other consistently, e.g. users listed on multiple teams or invite codes without teams.": "Reportar usuarios, equipos y códigos de invitación que no se referencian de forma consistente, por ejemplo usuarios en varios equipos o códigos de invitación sin equipo.",
+	"Results, scoreboards, and scorecards are written in the selected language, with heights in the selected units.":                                         "Los resultados, las tablas de puntuaciones y las tarjetas se escriben en el idioma seleccionado, con alturas en las unidades seleccionadas.",
+	"Roster CSV":   "CSV de participantes",
+	"Routes":       "Rutas",
+	"Routes (CSV)": "Rutas (CSV)",
+	"Routes CSV":   "CSV de rutas",
+	"Save the competition's routes, final standings, and climbs to a new archive using the archive ID entered above, or list existing archives.": "Guardar las rutas, la clasificación final y las escaladas de la competencia en un archivo nuevo usando el ID de archivo ingresado arriba, o listar los archivos existentes.",
+	"Scorecards": "Tarjetas de puntuación",
+	"Set database to be read-only or writable.": "Hacer que la base de datos sea de solo lectura o modificable.",
+	"Set team size": "Establecer tamaño de equipo",
+	"Set the maximum number of members on a team.": "Establecer el número máximo de miembros de un equipo.",
+	"Show initials only":                           "Mostrar solo iniciales",
+	"Size":                                         "Tamaño",
+	"Split":                                        "Dividir",
+	"Spreadsheet (XLSX)":                           "Hoja de cálculo (XLSX)",
+	"State":                                        "Estado",
+	"Statistics":                                   "Estadísticas",
+	"Team size":                                    "Tamaño de equipo",
+	"Teams":                                        "Equipos",
+	"Teams (CSV)":                                  "Equipos (CSV)",
+	"Teams can be identified by team ID or invite code, and users by user ID. List teams to see their IDs, invite codes, and members.": "Los equipos se pueden identificar por ID de equipo o código de invitación, y los usuarios por ID de usuario. Liste los equipos para ver sus ID, códigos de invitación y miembros.",
+	"Type 'REALLY CLEAR SCORES'": "Escriba 'REALLY CLEAR SCORES'",
+	"Units":                      "Unidades",
+	"Update now":                 "Actualizar ahora",
+	"Update routes":              "Actualizar rutas",
+	"Update scoreboard":          "Actualizar tabla de puntuaciones",
+	"Upload a Mountain Project route export in CSV format and compare it against routes with Mountain Project IDs, reporting mismatched names, grades, and heights.":                                                                               "Subir una exportación de rutas de Mountain Project en formato CSV y compararla con las rutas que tienen ID de Mountain Project, reportando nombres, grados y alturas que no coinciden.",
+	"Upload a roster in CSV format with <code>name</code>, <code>email</code>, and optional <code>team</code> and <code>category</code> columns to create accounts, teams, and invite codes. Participants who are already registered are skipped.": "Subir una lista de participantes en formato CSV con las columnas <code>name</code> y <code>email</code>, y opcionalmente <code>team</code> y <code>category</code>, para crear cuentas, equipos y códigos de invitación. Se omiten los participantes que ya están registrados.",
+	"Upload new area and route data in CSV format and use it to replace the existing data. Alternatively, upload a single JSON or YAML file containing a list of areas, each with a nested list of routes.":                                        "Subir datos nuevos de áreas y rutas en formato CSV y usarlos para reemplazar los datos existentes. También se puede subir un solo archivo JSON o YAML con una lista de áreas, cada una con una lista anidada de rutas.",
+	"User":          "Usuario",
+	"Users":         "Usuarios",
+	"Users (CSV)":   "Usuarios (CSV)",
+	"Verify climbs": "Verificar escaladas",
+	"View a printable list of a user's climbs grouped by area, or of the climbs of each member of a team identified by team ID or invite code.": "Ver una lista imprimible de las escaladas de un usuario agrupadas por área, o de las escaladas de cada miembro de un equipo identificado por ID de equipo o código de invitación.",
+	"View climbs": "Ver escaladas",
+	"View or change a user's recorded climbs. A reason must be supplied for each change; it's saved along with the change. To view, change, or verify climbs that a user reported on a team that they later left, also enter that team.": "Ver o cambiar las escaladas registradas de un usuario. Se requiere una razón para cada cambio; se guarda junto con el cambio. Para ver, cambiar o verificar escaladas que un usuario registró en un equipo que luego dejó, ingrese también ese equipo.",
+	"View per-route and per-area statistics for the current competition. Climbs of routes requiring verification are only counted once approved.":                                                                                        "Ver estadísticas por ruta y por área de la competencia actual. Las escaladas de rutas que requieren verificación solo se cuentan después de ser aprobadas.",
+	"View per-team or per-user scoreboards. Scores are recomputed at most every 30 seconds unless updated explicitly. If an archive ID is supplied, the archived competition's final standings are displayed instead.":                   "Ver tablas de puntuaciones por equipo o por usuario. Las puntuaciones se recalculan como máximo cada 30 segundos a menos que se actualicen explícitamente. Si se proporciona un ID de archivo, se muestra la clasificación final de la competencia archivada.",
+	"View scores": "Ver puntuaciones",
+	"Writable":    "Modificable",
+
+	// Climb states and verification statuses.
+	"Flash":    "Flash",
+	"Lead":     "Lead",
+	"Top":      "Top",
+	"Top-rope": "Top-rope",
+	"Zone":     "Zona",
+	"pending":  "pendiente",
+	"rejected": "rechazada",
+
+	// Scoreboards and scorecards.
+	"(left team)":                            "(se retiró)",
+	"Climber":                                "Escalador(a)",
+	"Climbs":                                 "Escaladas",
+	"Grade":                                  "Grado",
+	"Height":                                 "Altura",
+	"Points":                                 "Puntos",
+	"Route":                                  "Ruta",
+	"Score":                                  "Puntuación",
+	"Scorecard":                              "Tarjeta de puntuación",
+	"Scorecard: %s":                          "Tarjeta de puntuación: %s",
+	"Scores":                                 "Puntuaciones",
+	"Style":                                  "Estilo",
+	"Team":                                   "Equipo",
+	"Team total: %d points, %d climb(s), %s": "Total del equipo: %d punto(s), %d escalada(s), %s",
+	"Team: %s":                               "Equipo: %s",
+	"Total (%d climb(s))":                    "Total (%d escalada(s))",
+	"Updated %s":                             "Actualizado %s",
+
+	// Statistics.
+	"Area":             "Área",
+	"Area statistics":  "Estadísticas por área",
+	"Ascents":          "Ascensos",
+	"Avg. points":      "Puntos prom.",
+	"Climbers":         "Escaladores",
+	"Most climbed":     "Más escaladas",
+	"Route statistics": "Estadísticas por ruta",
+	"Sends":            "Encadenes",
+	"Sent":             "Encadenada",
+	"TR":               "TR",
+
+	// Awards.
+	"%s place":                   "%s lugar",
+	"Awarded to":                 "Otorgado a",
+	"Certificate of Achievement": "Certificado de logro",
+	"Climbs: %d (%s)":            "Escaladas: %d (%s)",
+	"Final standings":            "Clasificación final",
+	"Place":                      "Lugar",
+	"Score: %d":                  "Puntuación: %d",
+	"Uncategorized":              "Sin categoría",
+
+	// Results of admin operations.
+	"%d archive(s)":                                  "%d archivo(s)",
+	"%d climb(s) for %v (%q) in %v":                  "%d escalada(s) de %v (%q) en %v",
+	"%d climb(s) in team %v, which the user left":    "%d escalada(s) en el equipo %v, que el usuario dejó",
+	"%d correction(s)":                               "%d corrección(es)",
+	"%d pending climb(s)":                            "%d escalada(s) pendiente(s)",
+	"%d team(s)":                                     "%d equipo(s)",
+	"%q: competition %q archived %v with %d team(s)": "%q: competencia %q archivada %v con %d equipo(s)",
+	"%v (%q, team %q): %v %q %v":                     "%v (%q, equipo %q): %v %q %v",
+	"%v (fixed)":                                     "%v (reparado)",
+	"%v (must be fixed manually)":                    "%v (se debe reparar manualmente)",
+	"%v (unknown route): %v":                         "%v (ruta desconocida): %v",
+	"%v, %d attempt(s)":                              "%v, %d intento(s)",
+	"%v: MP route %v not found":                      "%v: no se encontró la ruta %v de MP",
+	"%v: grade %q doesn't match MP grade %q":         "%v: el grado %q no coincide con el grado %q de MP",
+	"%v: height %v doesn't match MP length %v":       "%v: la altura %v no coincide con el largo %v de MP",
+	"%v: name %q doesn't match MP name %q":           "%v: el nombre %q no coincide con el nombre %q de MP",
+	"Approved %v (%q) climb of %v (%v)":              "Se aprobó la escalada de %v (%q) en %v (%v)",
+	"Archived %d team(s) to %q":                      "Se archivaron %d equipo(s) en %q",
+	"Archives don't record user IDs, so the user's name and climbs remain in any archives": "Los archivos no guardan los ID de usuario, así que el nombre y las escaladas del usuario permanecen en los archivos",
+	"Changed %v (%q) climb of %v from %v to %v":                                            "Se cambió la escalada de %v (%q) en %v de %v a %v",
+	"Changed invite code for team %q from %v to %v":                                        "Se cambió el código de invitación del equipo %q de %v a %v",
+	"Checked %d user(s), %d team(s), and %d invite(s); found %d problem(s)":                "Se revisaron %d usuario(s), %d equipo(s) y %d invitación(es); se encontraron %d problema(s)",
+	"Cleared all scores":           "Se borraron todas las puntuaciones",
+	"Cleared all scores and teams": "Se borraron todas las puntuaciones y todos los equipos",
+	"Client log entries can't be deleted individually; they expire per the %q log's retention period": "Las entradas del registro del cliente no se pueden borrar individualmente; caducan según el período de retención del registro %q",
+	"Created %d, skipped %d, and failed %d user(s)":                                                   "Usuarios creados: %d, omitidos: %d, fallidos: %d",
+	"Deleted %d correction(s)":                                                                        "Se borraron %d corrección(es)",
+	"Deleted %d empty team(s)":                                                                        "Se borraron %d equipo(s) vacío(s)",
+	"Deleted auth user %v":                                                                            "Se borró el usuario de autenticación %v",
+	"Deleted team %v (%q) and its invite code %v since the user was its only member":                  "Se borró el equipo %v (%q) y su código de invitación %v porque el usuario era su único miembro",
+	"Deleted user doc":                                                                                "Se borró el documento del usuario",
+	"Erased user %v":                                                                                  "Se borró el usuario %v",
+	"Filled in %d empty field(s)":                                                                     "Se completaron %d campo(s) vacío(s)",
+	"Found %d empty field(s) that can be filled in":                                                   "Se encontraron %d campo(s) vacío(s) que se pueden completar",
+	"Invite %v points at nonexistent team %v":                                                         "La invitación %v apunta al equipo inexistente %v",
+	"Invite %v points at team %v, which has invite %v":                                                "La invitación %v apunta al equipo %v, que tiene la invitación %v",
+	"Merged team %q into %q":                                                                          "Se unió el equipo %q con %q",
+	"Moved %q from team %q to new team %q with invite code %v":                                        "Se movió a %q del equipo %q al equipo nuevo %q con el código de invitación %v",
+	"Moved %q to team %q":                                                                             "Se movió a %q al equipo %q",
+	"Recorded %d attempt(s)":                                                                          "Se registraron %d intento(s)",
+	"Rejected %v (%q) climb of %v (%v)":                                                               "Se rechazó la escalada de %v (%q) en %v (%v)",
+	"Removed from team %v (%q)":                                                                       "Se quitó del equipo %v (%q)",
+	"Renamed team %q to %q":                                                                           "Se renombró el equipo %q a %q",
+	"Row %d (%v): created":                                                                            "Fila %d (%v): creado",
+	"Row %d (%v): failed: %v":                                                                         "Fila %d (%v): falló: %v",
+	"Row %d (%v): skipped: %v":                                                                        "Fila %d (%v): omitido: %v",
+	"Set database readonly state to %v":                                                               "Se estableció el estado de solo lectura de la base de datos a %v",
+	"Set public scoreboard to %v (anonymized: %v)":                                                    "Se estableció la tabla de puntuaciones pública a %v (anonimizada: %v)",
+	"Set team size to %d":                                                                             "Se estableció el tamaño de equipo a %d",
+	"Skipped %d team(s) that changed while deleting":                                                  "Se omitieron %d equipo(s) que cambiaron durante el borrado",
+	"Team %v (%q) has %d members":                                                                     "El equipo %v (%q) tiene %d miembros",
+	"Team %v (%q) has invite %v belonging to team %v":                                                 "El equipo %v (%q) tiene la invitación %v que pertenece al equipo %v",
+	"Team %v (%q) has invite %v pointing at team %v":                                                  "El equipo %v (%q) tiene la invitación %v que apunta al equipo %v",
+	"Team %v (%q) has no invite code":                                                                 "El equipo %v (%q) no tiene código de invitación",
+	"Team %v (%q) has nonexistent invite %v":                                                          "El equipo %v (%q) tiene la invitación inexistente %v",
+	"Team %v (%q) lists nonexistent user %v":                                                          "El equipo %v (%q) incluye al usuario inexistente %v",
+	"Updated standings for %d team(s)":                                                                "Se actualizó la clasificación de %d equipo(s)",
+	"User %v (%q) is also listed on team %v":                                                          "El usuario %v (%q) también está en el equipo %v",
+	"User %v (%q) is listed on team %v but doesn't reference it":                                      "El usuario %v (%q) está en el equipo %v pero no lo referencia",
+	"User %v (%q) references nonexistent team %v":                                                     "El usuario %v (%q) referencia el equipo inexistente %v",
+	"User %v (%q) references team %v, which doesn't list them as a member":                            "El usuario %v (%q) referencia el equipo %v, que no lo incluye como miembro",
+	"Wrote %d area(s) and %d route(s)":                                                                "Se escribieron %d área(s) y %d ruta(s)",
+
+	// Errors.
+	"Archive %q already exists":                          "El archivo %q ya existe",
+	"Area data not supplied":                             "No se proporcionaron datos de áreas",
+	"Attempts can only be recorded for tops and zones":   "Solo se pueden registrar intentos para tops y zonas",
+	"Awards don't support archives":                      "Los premios no admiten archivos",
+	"Bad action %q":                                      "Acción inválida %q",
+	"Bad archive ID %q":                                  "ID de archivo inválido %q",
+	"Bad climb state %q":                                 "Estado de escalada inválido %q",
+	"Bad competition %q":                                 "Competencia inválida %q",
+	"Bad method %q":                                      "Método inválido %q",
+	"Bad name: %v":                                       "Nombre inválido: %v",
+	"Bad number of attempts %q":                          "Número de intentos inválido %q",
+	"Bad number of places %q":                            "Número de lugares inválido %q",
+	"Bad refresh: %v":                                    "Intervalo de actualización inválido: %v",
+	"Bad view %q":                                        "Vista inválida %q",
+	"Can't merge team with itself":                       "No se puede unir un equipo consigo mismo",
+	"Confirmation doesn't match user":                    "La confirmación no coincide con el usuario",
+	"Didn't confirm that we really want to clear scores": "No se confirmó que realmente se quieren borrar las puntuaciones",
+	"Exactly one of user or team must be supplied":       "Se debe proporcionar un usuario o un equipo, pero no ambos",
+	"Failed changing invite code: %v":                    "Error en cambiar el código de invitación: %v",
+	"Failed checking password: %v":                       "Error en revisar la contraseña: %v",
+	"Failed committing fixes: %v":                        "Error en guardar las reparaciones: %v",
+	"Failed creating Firestore client: %v":               "Error en crear el cliente de Firestore: %v",
+	"Failed decoding %v: %v":                             "Error en decodificar %v: %v",
+	"Failed deleting auth user: %v":                      "Error en borrar el usuario de autenticación: %v",
+	"Failed deleting invite doc %v: %v":                  "Error en borrar el documento de invitación %v: %v",
+	"Failed deleting standings chunk doc %v: %v":         "Error en borrar el documento de clasificación %v: %v",
+	"Failed deleting standings doc: %v":                  "Error en borrar el documento de clasificación: %v",
+	"Failed deleting team doc %v: %v":                    "Error en borrar el documento de equipo %v: %v",
+	"Failed deleting teams: %v":                          "Error en borrar los equipos: %v",
+	"Failed erasing user: %v":                            "Error en borrar el usuario: %v",
+	"Failed getting %v: %v":                              "Error en obtener %v: %v",
+	"Failed getting archive: %v":                         "Error en obtener el archivo: %v",
+	"Failed getting climbs: %v":                          "Error en obtener las escaladas: %v",
+	"Failed getting corrections: %v":                     "Error en obtener las correcciones: %v",
+	"Failed getting data file: %v":                       "Error en obtener el archivo de datos: %v",
+	"Failed getting indexed data: %v":                    "Error en obtener los datos indexados: %v",
+	"Failed getting invite ref: %v":                      "Error en obtener la referencia de invitación: %v",
+	"Failed getting log entries: %v":                     "Error en obtener las entradas del registro: %v",
+	"Failed getting sorted data: %v":                     "Error en obtener los datos ordenados: %v",
+	"Failed getting standings chunk ref: %v":             "Error en obtener la referencia de clasificación: %v",
+	"Failed getting team doc: %v":                        "Error en obtener el documento de equipo: %v",
+	"Failed getting team ref: %v":                        "Error en obtener la referencia de equipo: %v",
+	"Failed getting team: %v":                            "Error en obtener el equipo: %v",
+	"Failed getting user doc: %v":                        "Error en obtener el documento de usuario: %v",
+	"Failed getting user ref: %v":                        "Error en obtener la referencia de usuario: %v",
+	"Failed loading invites: %v":                         "Error en cargar las invitaciones: %v",
+	"Failed loading scoreboard":                          "Error en cargar la tabla de puntuaciones",
+	"Failed loading scores: %v":                          "Error en cargar las puntuaciones: %v",
+	"Failed loading teams: %v":                           "Error en cargar los equipos: %v",
+	"Failed loading users: %v":                           "Error en cargar los usuarios: %v",
+	"Failed merging teams: %v":                           "Error en unir los equipos: %v",
+	"Failed moving user: %v":                             "Error en mover el usuario: %v",
+	"Failed parsing form data":                           "Error en analizar los datos del formulario",
+	"Failed reading %v data: %v":                         "Error en leer los datos %v: %v",
+	"Failed reading Mountain Project data: %v":           "Error en leer los datos de Mountain Project: %v",
+	"Failed reading area data: %v":                       "Error en leer los datos de áreas: %v",
+	"Failed reading roster: %v":                          "Error en leer la lista de participantes: %v",
+	"Failed reading route data: %v":                      "Error en leer los datos de rutas: %v",
+	"Failed setting readonly state: %v":                  "Error en establecer el estado de solo lectura: %v",
+	"Failed setting scoreboard state: %v":                "Error en establecer el estado de la tabla de puntuaciones: %v",
+	"Failed setting team size: %v":                       "Error en establecer el tamaño de equipo: %v",
+	"Failed sorting data: %v":                            "Error en ordenar los datos: %v",
+	"Failed splitting team: %v":                          "Error en dividir el equipo: %v",
+	"Failed updating climb: %v":                          "Error en actualizar la escalada: %v",
+	"Failed updating standings: %v":                      "Error en actualizar la clasificación: %v",
+	"Failed updating team: %v":                           "Error en actualizar el equipo: %v",
+	"Failed updating user: %v":                           "Error en actualizar el usuario: %v",
+	"Failed writing %v: %v":                              "Error en escribir %v: %v",
+	"Failed writing PDF: %v":                             "Error en escribir el PDF: %v",
+	"Failed writing areas: %v":                           "Error en escribir las áreas: %v",
+	"Failed writing export: %v":                          "Error en escribir la exportación: %v",
+	"Failed writing route data: %v":                      "Error en escribir los datos de rutas: %v",
+	"Failed writing routes: %v":                          "Error en escribir las rutas: %v",
+	"Failed writing scores: %v":                          "Error en escribir las puntuaciones: %v",
+	"Failed writing stats: %v":                           "Error en escribir las estadísticas: %v",
+	"Failed writing teams: %v":                           "Error en escribir los equipos: %v",
+	"Failed writing template: %v":                        "Error en escribir la plantilla: %v",
+	"Failed writing to %v: %v":                           "Error en escribir en %v: %v",
+	"Failed writing users: %v":                           "Error en escribir los usuarios: %v",
+	"Failed writing workbook: %v":                        "Error en escribir el libro de cálculo: %v",
+	"Incorrect password":                                 "Contraseña incorrecta",
+	"JSON export doesn't support archives":               "La exportación JSON no admite archivos",
+	"Merged team would have %d members":                  "El equipo unido tendría %d miembros",
+	"Mountain Project data not supplied":                 "No se proporcionaron datos de Mountain Project",
+	"Reason not supplied":                                "No se proporcionó una razón",
+	"Roster not supplied":                                "No se proporcionó la lista de participantes",
+	"Route %q doesn't require verification":              "La ruta %q no requiere verificación",
+	"Route %q is already %v":                             "La ruta %q ya está en %v",
+	"Route %q not found":                                 "No se encontró la ruta %q",
+	"Route data not supplied":                            "No se proporcionaron datos de rutas",
+	"Scoreboard not available":                           "Tabla de puntuaciones no disponible",
+	"Team %q is full":                                    "El equipo %q está lleno",
+	"Team %q not found":                                  "No se encontró el equipo %q",
+	"Team not found":                                     "No se encontró el equipo",
+	"Team size must be between 1 and %d":                 "El tamaño de equipo debe estar entre 1 y %d",
+	"User %q already left team %q":                       "El usuario %q ya dejó el equipo %q",
+	"User %q hasn't climbed route %q":                    "El usuario %q no ha escalado la ruta %q",
+	"User %q is already on team %q":                      "El usuario %q ya está en el equipo %q",
+	"User %q is listed on both teams":                    "El usuario %q está en ambos equipos",
+	"User %q is the only member of team %q":              "El usuario %q es el único miembro del equipo %q",
+	"User %q not found":                                  "No se encontró el usuario %q",
+	"User %q not listed in team %q":                      "El usuario %q no está en el equipo %q",
+	"User %q not listed on team %q":                      "El usuario %q no está en el equipo %q",
+	"User %q not on team %q":                             "El usuario %q no está en el equipo %q",
+	"User %q previously left team %q":                    "El usuario %q dejó el equipo %q anteriormente",
+	"User not supplied":                                  "No se proporcionó un usuario",
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleMountainProject handles a "mountainProject" POST request.
+// It reads an uploaded Mountain Project route export in CSV format and matches its
+// routes against the existing route data by MPID, reporting mismatched names, grades,
+// and heights. If the "mpUpdate" parameter is set to "1", empty fields are filled in
+// with Mountain Project's data and the updated routes are written to Cloud Firestore.
+func handleMountainProject(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	mpFile, _, err := r.FormFile("mpRoutes")
+	if err != nil {
+		http.Error(w, loc.T("Mountain Project data not supplied"), http.StatusBadRequest)
+		return
+	}
+	mpRoutes, err := readMPRoutes(mpFile)
+	if err != nil {
+		http.Error(w, loc.T("Failed reading Mountain Project data: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		http.Error(w, loc.T("Failed getting sorted data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	areas, routes := sorted.Split()
+	filled, report := mergeMPRoutes(routes, mpRoutes, loc)
+
+	if r.FormValue("mpUpdate") == "1" && filled > 0 {
+		sd, err := db.NewSortedData(areas, routes)
+		if err != nil {
+			http.Error(w, loc.T("Failed sorting data: %v", err), http.StatusInternalServerError)
+			return
+		}
+		batch := client.Batch()
+		batch.Set(client.Doc(db.SortedDataDocPath(comp)), sd)
+		batch.Set(client.Doc(db.IndexedDataDocPath(comp)), db.NewIndexedData(areas, routes))
+		log.Printf("Writing %d field(s) from Mountain Project data", filled)
+		if _, err := batch.Commit(ctx); err != nil {
+			http.Error(w, loc.T("Failed writing route data: %v", err), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, loc.T("Filled in %d empty field(s)", filled))
+	} else {
+		fmt.Fprintln(w, loc.T("Found %d empty field(s) that can be filled in", filled))
+	}
+	for _, ln := range report {
+		fmt.Fprintln(w, ln)
+	}
+}
+
+// mpRoute describes a route in Mountain Project's CSV route export.
+type mpRoute struct {
+	Name   string
+	URL    string // e.g. "https://www.mountainproject.com/route/105748391/night-vision"
+	Rating string // e.g. "5.10b PG13" or "V4"
+	Length int    // in feet
+}
+
+// mpRouteCols describes the columns in Mountain Project's CSV route export
+// that we use. Other columns (e.g. "Location", "Avg Stars") are ignored.
+var mpRouteCols = []csvColumn{
+	{name: "route", aliases: []string{"name"}},
+	{name: "url"},
+	{name: "rating", aliases: []string{"grade"}},
+	{name: "length", optional: true},
+}
+
+// readMPRoutes reads a Mountain Project CSV route export from r.
+// The returned map is keyed by Mountain Project route ID.
+func readMPRoutes(r io.Reader) (map[string]mpRoute, error) {
+	var routes []mpRoute
+	if err := readCSV(r, mpRouteCols, ignoreUnknown, func() map[string]interface{} {
+		routes = append(routes, mpRoute{})
+		rt := &routes[len(routes)-1]
+		return map[string]interface{}{
+			"route":  &rt.Name,
+			"url":    &rt.URL,
+			"rating": &rt.Rating,
+			"length": &rt.Length,
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]mpRoute, len(routes))
+	for _, rt := range routes {
+		id := getMPID(rt.URL)
+		if id == "" {
+			return nil, fmt.Errorf("failed to get ID from URL %q for %q", rt.URL, rt.Name)
+		}
+		m[id] = rt
+	}
+	return m, nil
+}
+
+// mpURLRegexp matches a Mountain Project route URL. The first group contains the route ID.
+var mpURLRegexp = regexp.MustCompile(`/route/(\d+)(/|$)`)
+
+// getMPID returns the Mountain Project route ID from url, or an empty string if
+// the ID couldn't be found.
+func getMPID(url string) string {
+	if ms := mpURLRegexp.FindStringSubmatch(url); ms != nil {
+		return ms[1]
+	}
+	return ""
+}
+
+// getMPGrade returns the grade portion of a Mountain Project rating, dropping
+// protection ratings and other suffixes, e.g. "5.10b PG13" becomes "5.10b".
+func getMPGrade(rating string) string {
+	if fs := strings.Fields(rating); len(fs) > 0 {
+		return fs[0]
+	}
+	return ""
+}
+
+// mergeMPRoutes compares routes against the Mountain Project data in mp (keyed by MPID).
+// Empty names, grades, and heights in routes are filled in from mp, and the number of
+// filled-in fields is returned. Human-readable descriptions of mismatches between
+// non-empty fields and routes that weren't found in mp are also returned (localized using loc).
+func mergeMPRoutes(routes []db.Route, mp map[string]mpRoute, loc *localizer) (filled int, report []string) {
+	for i := range routes {
+		rt := &routes[i]
+		if rt.MPID == "" {
+			continue
+		}
+		mr, ok := mp[rt.MPID]
+		if !ok {
+			report = append(report, loc.T("%v: MP route %v not found", rt.ID, rt.MPID))
+			continue
+		}
+
+		if rt.Name == "" {
+			rt.Name = mr.Name
+			filled++
+		} else if !strings.EqualFold(rt.Name, mr.Name) {
+			report = append(report, loc.T("%v: name %q doesn't match MP name %q", rt.ID, rt.Name, mr.Name))
+		}
+
+		if grade := getMPGrade(mr.Rating); grade == "" {
+			// Nothing to compare against.
+		} else if rt.Grade == "" {
+			rt.Grade = grade
+			filled++
+		} else if rt.Grade != grade {
+			report = append(report, loc.T("%v: grade %q doesn't match MP grade %q", rt.ID, rt.Grade, grade))
+		}
+
+		if mr.Length == 0 {
+			// Nothing to compare against.
+		} else if rt.Height == 0 {
+			rt.Height = mr.Length
+			filled++
+		} else if rt.Height != mr.Length {
+			report = append(report, loc.T("%v: height %v doesn't match MP length %v", rt.ID, loc.height(rt.Height), loc.height(mr.Length)))
+		}
+	}
+	return filled, report
+}
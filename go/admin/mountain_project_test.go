@@ -0,0 +1,62 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestReadMPRoutes(t *testing.T) {
+	const in = "Route,Location,URL,Avg Stars,Your Stars,Route Type,Rating,Pitches,Length,Area Latitude,Area Longitude\n" +
+		"Night Vision,Cave > Crag,https://www.mountainproject.com/route/105748391/night-vision,3.2,-1,Sport,5.10b PG13,1,80,18.1,-66.2\n" +
+		"Boulder,Cave > Crag,https://www.mountainproject.com/route/123,2.0,-1,Boulder,V4,1,,18.1,-66.2\n"
+	got, err := readMPRoutes(strings.NewReader(in))
+	if err != nil {
+		t.Fatal("readMPRoutes failed: ", err)
+	}
+	want := map[string]mpRoute{
+		"105748391": {"Night Vision", "https://www.mountainproject.com/route/105748391/night-vision", "5.10b PG13", 80},
+		"123":       {"Boulder", "https://www.mountainproject.com/route/123", "V4", 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readMPRoutes returned %+v; want %+v", got, want)
+	}
+
+	if _, err := readMPRoutes(strings.NewReader("Route,URL,Rating\nFoo,https://example.org/,5.9\n")); err == nil {
+		t.Error("readMPRoutes unexpectedly succeeded for URL without ID")
+	}
+}
+
+func TestMergeMPRoutes(t *testing.T) {
+	mp := map[string]mpRoute{
+		"1": {Name: "Night Vision", Rating: "5.10b PG13", Length: 80},
+		"2": {Name: "Other Route", Rating: "5.9", Length: 60},
+		"3": {Name: "Third", Rating: "", Length: 0},
+	}
+	routes := []db.Route{
+		{ID: "a", MPID: "1"},
+		{ID: "b", Name: "Other route", Grade: "5.9+", Height: 60, MPID: "2"},
+		{ID: "c", Name: "Third", Grade: "5.7", Height: 40, MPID: "3"},
+		{ID: "d", Name: "Missing", MPID: "4"},
+		{ID: "e", Name: "No MPID"},
+	}
+	filled, report := mergeMPRoutes(routes, mp, defaultLocalizer)
+	if filled != 3 {
+		t.Errorf("mergeMPRoutes filled %d field(s); want 3", filled)
+	}
+	if want := (db.Route{ID: "a", Name: "Night Vision", Grade: "5.10b", Height: 80, MPID: "1"}); !reflect.DeepEqual(routes[0], want) {
+		t.Errorf("mergeMPRoutes updated route to %+v; want %+v", routes[0], want)
+	}
+	if want := []string{
+		`b: grade "5.9+" doesn't match MP grade "5.9"`,
+		`d: MP route 4 not found`,
+	}; !reflect.DeepEqual(report, want) {
+		t.Errorf("mergeMPRoutes reported %q; want %q", report, want)
+	}
+}
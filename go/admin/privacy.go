@@ -0,0 +1,382 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/logging/logadmin"
+	firebase "firebase.google.com/go"
+	"firebase.google.com/go/auth"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/api/iterator"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// clientLogName is the name of the log written by the "Log" Cloud Function.
+const clientLogName = "client"
+
+// handleExportUser handles an "exportUser" POST request.
+// It writes a JSON attachment containing all data held about the user identified by the
+// "privacyUser" parameter (a user ID or email address) in competition comp, including
+// their Firebase Auth record and client log entries.
+func handleExportUser(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	ac, err := getAuthClient(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	uid, rec, err := lookUpUser(ctx, ac, r.FormValue("privacyUser"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exp := userExport{Time: time.Now(), Competition: comp, UID: uid}
+	if rec != nil {
+		exp.Auth = newAuthExport(rec)
+	}
+
+	var user db.User
+	if ok, err := getDocIfExists(ctx, client.Collection(db.UserCollectionPath(comp)).Doc(uid), &user); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if ok {
+		exp.User = &user
+	}
+	teams, err := findUserTeams(ctx, client, comp, uid)
+	if err != nil {
+		http.Error(w, loc.T("Failed loading teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	for _, t := range teams {
+		// Only include the user's own data, not that of their teammates.
+		exp.Teams = append(exp.Teams, teamExport{ID: t.ref.ID, Name: t.team.Name, Member: t.team.Users[uid]})
+	}
+	var verifs db.UserVerifications
+	if ok, err := getDocIfExists(ctx, client.Collection(db.VerificationCollectionPath(comp)).Doc(uid), &verifs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if ok {
+		exp.Verifications = verifs.Climbs
+	}
+	if exp.Corrections, err = getCorrections(ctx, client, comp, uid); err != nil {
+		http.Error(w, loc.T("Failed getting corrections: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if exp.LogEntries, err = getClientLogEntries(ctx, uid); err != nil {
+		http.Error(w, loc.T("Failed getting log entries: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=user-%v.json", uid))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(exp); err != nil {
+		http.Error(w, loc.T("Failed writing export: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// handleEraseUser handles an "eraseUser" POST request.
+// It deletes the user identified by the "privacyUser" parameter (a user ID or email address)
+// from competition comp, removing them from any teams that list them in the same way that
+// the "Test" function does. The user's verifications, corrections, and Firebase Auth record
+// are also deleted. The "privacyConfirm" parameter must match "privacyUser".
+// Archives don't include user IDs, so the user's name and climbs remain in them.
+func handleEraseUser(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	id := strings.TrimSpace(r.FormValue("privacyUser"))
+	if id == "" || strings.TrimSpace(r.FormValue("privacyConfirm")) != id {
+		http.Error(w, loc.T("Confirmation doesn't match user"), http.StatusBadRequest)
+		return
+	}
+	ac, err := getAuthClient(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	uid, rec, err := lookUpUser(ctx, ac, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Erasing user %v from competition %q", uid, comp)
+	lines, err := eraseUserDocs(ctx, client, comp, uid, loc)
+	if err != nil {
+		http.Error(w, loc.T("Failed erasing user: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rec != nil {
+		if err := ac.DeleteUser(ctx, uid); err != nil {
+			http.Error(w, loc.T("Failed deleting auth user: %v", err), http.StatusInternalServerError)
+			return
+		}
+		lines = append(lines, loc.T("Deleted auth user %v", rec.Email))
+	}
+
+	fmt.Fprintln(w, loc.T("Erased user %v", uid))
+	for _, ln := range lines {
+		fmt.Fprintln(w, ln)
+	}
+	fmt.Fprintln(w, loc.T("Client log entries can't be deleted individually; they expire per the %q log's retention period",
+		clientLogName))
+	fmt.Fprintln(w, loc.T("Archives don't record user IDs, so the user's name and climbs remain in any archives"))
+}
+
+// eraseUserDocs deletes user uid's docs from competition comp and removes them from teams
+// as described in handleEraseUser. Writes are committed in multiple batches if needed,
+// so some writes may have been committed if an error is returned; erasing the user again
+// completes the deletion. Lines describing the changes (localized using loc) are returned.
+func eraseUserDocs(ctx context.Context, client *firestore.Client, comp, uid string, loc *localizer) ([]string, error) {
+	var lines []string
+	batch := client.Batch()
+	var nwrites int
+	// reserve commits batch if it lacks room for n more writes.
+	reserve := func(n int) error {
+		if nwrites+n <= maxBatchWrites {
+			nwrites += n
+			return nil
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return fmt.Errorf("failed committing batched writes: %v", err)
+		}
+		batch, nwrites = client.Batch(), n
+		return nil
+	}
+
+	// removedLine describes the user's removal from team t.
+	removedLine := func(t userTeam, deleted bool) string {
+		if deleted {
+			return loc.T("Deleted team %v (%q) and its invite code %v since the user was its only member",
+				t.ref.ID, t.team.Name, t.team.Invite)
+		}
+		return loc.T("Removed from team %v (%q)", t.ref.ID, t.team.Name)
+	}
+
+	user, teamDeleted, err := db.DeleteUser(ctx, client, batch, comp, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed deleting user: %v", err)
+	} else if user != nil {
+		nwrites += 3 // upper bound
+		lines = append(lines, loc.T("Deleted user doc"))
+	}
+
+	// Also remove the user from teams that they left after reporting climbs.
+	teams, err := findUserTeams(ctx, client, comp, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading teams: %v", err)
+	}
+	for _, t := range teams {
+		if user != nil && t.ref.ID == user.Team {
+			lines = append(lines, removedLine(t, teamDeleted))
+			continue
+		}
+		if err := reserve(2); err != nil {
+			return nil, err
+		}
+		lines = append(lines, removedLine(t, db.RemoveTeamUser(client, batch, comp, t.ref, t.team, uid)))
+	}
+
+	if err := reserve(1); err != nil {
+		return nil, err
+	}
+	batch.Delete(client.Collection(db.VerificationCollectionPath(comp)).Doc(uid))
+	corrIt := client.Collection(db.CorrectionCollectionPath(comp)).Where("user", "==", uid).Documents(ctx)
+	var ncorr int
+	for {
+		snap, err := corrIt.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed getting corrections: %v", err)
+		}
+		if err := reserve(1); err != nil {
+			return nil, err
+		}
+		batch.Delete(snap.Ref)
+		ncorr++
+	}
+	if ncorr > 0 {
+		lines = append(lines, loc.T("Deleted %d correction(s)", ncorr))
+	}
+
+	if _, err := batch.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed committing batched writes: %v", err)
+	}
+
+	// Make the next scoreboard request recompute the standings without the user.
+	if err := invalidateStandings(ctx, client, comp); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// getAuthClient returns a Firebase Auth client.
+func getAuthClient(ctx context.Context) (*auth.Client, error) {
+	app, err := firebase.NewApp(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating Firebase app: %v", err)
+	}
+	ac, err := app.Auth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting auth client: %v", err)
+	}
+	return ac, nil
+}
+
+// lookUpUser returns the user ID and Firebase Auth record for id, which may be either
+// a user ID or an email address. If id is a user ID without an auth record (e.g. because
+// the record was already deleted), id is returned with a nil record.
+func lookUpUser(ctx context.Context, ac *auth.Client, id string) (string, *auth.UserRecord, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", nil, fmt.Errorf("user not supplied")
+	}
+	if strings.Contains(id, "@") {
+		rec, err := ac.GetUserByEmail(ctx, id)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed getting user with email %v: %v", id, err)
+		}
+		return rec.UID, rec, nil
+	}
+	rec, err := ac.GetUser(ctx, id)
+	if auth.IsUserNotFound(err) {
+		return id, nil, nil
+	} else if err != nil {
+		return "", nil, fmt.Errorf("failed getting user %v: %v", id, err)
+	}
+	return id, rec, nil
+}
+
+// userTeam describes a team that lists a user.
+type userTeam struct {
+	ref  *firestore.DocumentRef
+	team *db.Team
+}
+
+// findUserTeams returns all of competition comp's teams that list user uid,
+// including teams that the user left after reporting climbs.
+func findUserTeams(ctx context.Context, client *firestore.Client, comp, uid string) ([]userTeam, error) {
+	var teams []userTeam
+	err := loadCollection(ctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var t db.Team
+		if err := snap.DataTo(&t); err != nil {
+			return err
+		}
+		if _, ok := t.Users[uid]; ok {
+			teams = append(teams, userTeam{snap.Ref, &t})
+		}
+		return nil
+	})
+	return teams, err
+}
+
+// getClientLogEntries returns the entries written by the "Log" Cloud Function for user uid.
+func getClientLogEntries(ctx context.Context, uid string) ([]logEntryExport, error) {
+	project := os.Getenv("GCP_PROJECT") // set at deployment
+	client, err := logadmin.NewClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	filter := fmt.Sprintf(`logName = "projects/%s/logs/%s" AND labels.uid = %q`, project, clientLogName, uid)
+	var entries []logEntryExport
+	it := client.Entries(ctx, logadmin.Filter(filter))
+	for {
+		e, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		exp := logEntryExport{Time: e.Timestamp, Severity: e.Severity.String(), Labels: e.Labels}
+		if msg, ok := e.Payload.(proto.Message); ok {
+			var m jsonpb.Marshaler
+			s, err := m.MarshalToString(msg)
+			if err != nil {
+				return nil, err
+			}
+			exp.Payload = json.RawMessage(s)
+		} else {
+			exp.Payload = e.Payload
+		}
+		entries = append(entries, exp)
+	}
+	return entries, nil
+}
+
+// userExport contains all of the data held about a user.
+type userExport struct {
+	Time          time.Time                  `json:"time"`
+	Competition   string                     `json:"competition"`
+	UID           string                     `json:"uid"`
+	Auth          *authExport                `json:"auth,omitempty"`
+	User          *db.User                   `json:"user,omitempty"`
+	Teams         []teamExport               `json:"teams,omitempty"`
+	Verifications map[string]db.Verification `json:"verifications,omitempty"`
+	Corrections   []db.Correction            `json:"corrections,omitempty"`
+	LogEntries    []logEntryExport           `json:"logEntries,omitempty"`
+}
+
+// authExport contains a user's Firebase Auth record.
+type authExport struct {
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"emailVerified"`
+	DisplayName   string    `json:"displayName,omitempty"`
+	PhoneNumber   string    `json:"phoneNumber,omitempty"`
+	PhotoURL      string    `json:"photoUrl,omitempty"`
+	Providers     []string  `json:"providers,omitempty"`
+	Created       time.Time `json:"created"`
+	LastLogin     time.Time `json:"lastLogin"`
+}
+
+// newAuthExport returns an authExport containing the data from rec.
+func newAuthExport(rec *auth.UserRecord) *authExport {
+	exp := &authExport{
+		Email:         rec.Email,
+		EmailVerified: rec.EmailVerified,
+		DisplayName:   rec.DisplayName,
+		PhoneNumber:   rec.PhoneNumber,
+		PhotoURL:      rec.PhotoURL,
+	}
+	for _, p := range rec.ProviderUserInfo {
+		exp.Providers = append(exp.Providers, p.ProviderID)
+	}
+	if rec.UserMetadata != nil {
+		exp.Created = time.Unix(0, rec.UserMetadata.CreationTimestamp*int64(time.Millisecond))
+		exp.LastLogin = time.Unix(0, rec.UserMetadata.LastLogInTimestamp*int64(time.Millisecond))
+	}
+	return exp
+}
+
+// teamExport contains a user's membership in a team.
+type teamExport struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Member db.TeamUser `json:"member"`
+}
+
+// logEntryExport contains a client log entry.
+type logEntryExport struct {
+	Time     time.Time         `json:"time"`
+	Severity string            `json:"severity"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Payload  interface{}       `json:"payload,omitempty"`
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"firebase.google.com/go/auth"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestNewAuthExport(t *testing.T) {
+	rec := &auth.UserRecord{
+		UserInfo: &auth.UserInfo{
+			Email:       "ann@example.com",
+			DisplayName: "Ann",
+		},
+		EmailVerified: true,
+		ProviderUserInfo: []*auth.UserInfo{
+			{ProviderID: "google.com"},
+			{ProviderID: "password"},
+		},
+		UserMetadata: &auth.UserMetadata{
+			CreationTimestamp:  1000,
+			LastLogInTimestamp: 2000,
+		},
+	}
+	got := newAuthExport(rec)
+	want := &authExport{
+		Email:         "ann@example.com",
+		EmailVerified: true,
+		DisplayName:   "Ann",
+		Providers:     []string{"google.com", "password"},
+		Created:       time.Unix(1, 0),
+		LastLogin:     time.Unix(2, 0),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newAuthExport(...) = %+v; want %+v", got, want)
+	}
+}
+
+func TestEraseUserDocs(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	const numCorr = 600 // enough to require multiple batches
+	ff.set("users/u1", map[string]interface{}{"name": "User 1", "team": "t1"})
+	ff.set("teams/t1", map[string]interface{}{"name": "Team 1", "invite": "000001", "users": map[string]interface{}{
+		"u1": map[string]interface{}{"name": "User 1", "climbs": map[string]interface{}{}},
+		"u2": map[string]interface{}{"name": "User 2", "climbs": map[string]interface{}{}},
+	}})
+	ff.set("invites/000001", map[string]interface{}{"team": "t1"})
+	ff.set("teams/t2", map[string]interface{}{"name": "Team 2", "invite": "000002", "users": map[string]interface{}{
+		"u1": map[string]interface{}{"name": "User 1", "climbs": map[string]interface{}{"r1": 1}, "left": true},
+	}})
+	ff.set("invites/000002", map[string]interface{}{"team": "t2"})
+	ff.set("verifications/u1", map[string]interface{}{"climbs": map[string]interface{}{}})
+	for i := 0; i < numCorr; i++ {
+		ff.set(fmt.Sprintf("corrections/c%d", i), map[string]interface{}{"user": "u1"})
+	}
+	ff.set("corrections/other", map[string]interface{}{"user": "u2"})
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	lines, err := eraseUserDocs(context.Background(), client, db.DefaultCompetition, "u1", defaultLocalizer)
+	if err != nil {
+		t.Fatal("eraseUserDocs failed: ", err)
+	}
+	want := []string{
+		"Deleted user doc",
+		`Removed from team t1 ("Team 1")`,
+		`Deleted team t2 ("Team 2") and its invite code 000002 since the user was its only member`,
+		fmt.Sprintf("Deleted %d correction(s)", numCorr),
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("eraseUserDocs returned %q; want %q", lines, want)
+	}
+
+	for _, tc := range []struct {
+		coll string
+		want int
+	}{
+		{"users", 0},
+		{"teams", 1},
+		{"invites", 1},
+		{"verifications", 0},
+		{"corrections", 1},
+	} {
+		if got := ff.count(tc.coll); got != tc.want {
+			t.Errorf("%q has %d doc(s); want %d", tc.coll, got, tc.want)
+		}
+	}
+	var team db.Team
+	if err := db.GetDoc(context.Background(), client.Doc("teams/t1"), &team); err != nil {
+		t.Fatal("Failed getting team: ", err)
+	} else if _, ok := team.Users["u1"]; ok || len(team.Users) != 1 {
+		t.Errorf("Team t1 has users %v; want only u2", team.Users)
+	}
+}
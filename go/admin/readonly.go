@@ -15,23 +15,23 @@ import (
 
 // handleReadonly handles a "readonly" POST request.
 // It updates the config so that the Firestore database cannot be modified by users.
-func handleReadonly(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	setReadonly(ctx, w, client, true)
+func handleReadonly(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	setReadonly(ctx, w, newLocalizer(r), client, comp, true)
 }
 
 // handleReadonly handles a "writable" POST request.
 // It updates the config so that the Firestore database is writable by users.
-func handleWritable(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	setReadonly(ctx, w, client, false)
+func handleWritable(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	setReadonly(ctx, w, newLocalizer(r), client, comp, false)
 }
 
-// setReadonly updates the global config doc's 'readonly' field.
-func setReadonly(ctx context.Context, w http.ResponseWriter, client *firestore.Client, readonly bool) {
-	if _, err := client.Doc(db.ConfigDocPath).Set(ctx, map[string]interface{}{
+// setReadonly updates the competition config doc's 'readonly' field.
+func setReadonly(ctx context.Context, w http.ResponseWriter, loc *localizer, client *firestore.Client, comp string, readonly bool) {
+	if _, err := client.Doc(db.ConfigDocPath(comp)).Set(ctx, map[string]interface{}{
 		"readonly": readonly,
 	}, firestore.MergeAll); err != nil {
-		http.Error(w, fmt.Sprintf("Failed setting readonly state: %v", err), http.StatusInternalServerError)
+		http.Error(w, loc.T("Failed setting readonly state: %v", err), http.StatusInternalServerError)
 		return
 	}
-	fmt.Fprintf(w, "Set database readonly state to %v", readonly)
+	fmt.Fprint(w, loc.T("Set database readonly state to %v", readonly))
 }
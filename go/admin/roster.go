@@ -0,0 +1,258 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"firebase.google.com/go/auth"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleRoster handles a "roster" POST request.
+// It reads an uploaded roster in CSV format and registers each listed participant,
+// creating Firebase Auth users, user docs, team docs, and invite codes as needed.
+// A line is written for each row describing whether it was created, skipped, or failed.
+func handleRoster(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	f, _, err := r.FormFile("roster")
+	if err != nil {
+		http.Error(w, loc.T("Roster not supplied"), http.StatusBadRequest)
+		return
+	}
+	rows, err := readRoster(f)
+	if err != nil {
+		http.Error(w, loc.T("Failed reading roster: %v", err), http.StatusBadRequest)
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ac, err := getAuthClient(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Existing teams are matched by name so the roster can be uploaded multiple times.
+	teams := make(map[string]*rosterTeam)
+	if err := loadCollection(ctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var t db.Team
+		err := snap.DataTo(&t)
+		teams[strings.ToLower(t.Name)] = &rosterTeam{snap.Ref, len(t.Users)}
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var created, skipped, failed int
+	var lines []string
+	for i, res := range checkRoster(rows, cfg.GetTeamSize()) {
+		row := &rows[i]
+		// Add 2 to get 1-indexed line numbers that account for the header row.
+		line := i + 2
+		switch {
+		case res.skip != "":
+			lines = append(lines, loc.T("Row %d (%v): skipped: %v", line, row.Email, res.skip))
+			skipped++
+		case res.err != nil:
+			lines = append(lines, loc.T("Row %d (%v): failed: %v", line, row.Email, res.err))
+			failed++
+		default:
+			if skip, err := registerUser(ctx, client, ac, comp, row, teams, cfg.GetTeamSize()); err != nil {
+				lines = append(lines, loc.T("Row %d (%v): failed: %v", line, row.Email, err))
+				failed++
+			} else if skip != "" {
+				lines = append(lines, loc.T("Row %d (%v): skipped: %v", line, row.Email, skip))
+				skipped++
+			} else {
+				lines = append(lines, loc.T("Row %d (%v): created", line, row.Email))
+				created++
+			}
+		}
+	}
+
+	fmt.Fprintln(w, loc.T("Created %d, skipped %d, and failed %d user(s)", created, skipped, failed))
+	for _, ln := range lines {
+		fmt.Fprintln(w, ln)
+	}
+}
+
+// rosterRow describes a participant listed in an uploaded roster.
+type rosterRow struct {
+	Name     string
+	Email    string
+	Team     string // team name; may be empty
+	Category string // may be empty
+}
+
+// rosterCols describes the columns in roster CSV data.
+var rosterCols = []csvColumn{
+	{name: "name"},
+	{name: "email"},
+	{name: "team", aliases: []string{"team_name"}, optional: true},
+	{name: "category", optional: true},
+}
+
+// readRoster reads a roster in CSV format from r.
+func readRoster(r io.Reader) ([]rosterRow, error) {
+	var rows []rosterRow
+	if err := readCSV(r, rosterCols, rejectUnknown, func() map[string]interface{} {
+		rows = append(rows, rosterRow{})
+		row := &rows[len(rows)-1]
+		return map[string]interface{}{
+			"name":     &row.Name,
+			"email":    &row.Email,
+			"team":     &row.Team,
+			"category": &row.Category,
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// rosterResult describes the result of checking a rosterRow.
+type rosterResult struct {
+	skip string // reason for skipping the row
+	err  error  // reason for failing the row
+}
+
+// checkRoster validates rows and returns a result for each. Names and emails in rows
+// are normalized. Rows with duplicate email addresses are skipped, and rows that would
+// give a team more than teamSize members fail.
+func checkRoster(rows []rosterRow, teamSize int) []rosterResult {
+	res := make([]rosterResult, len(rows))
+	emails := make(map[string]struct{})
+	teamCounts := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		var err error
+		if row.Name, err = checkName(row.Name); err != nil {
+			res[i].err = err
+			continue
+		}
+		if row.Team = strings.TrimSpace(row.Team); row.Team != "" {
+			if row.Team, err = checkName(row.Team); err != nil {
+				res[i].err = fmt.Errorf("bad team: %v", err)
+				continue
+			}
+		}
+		row.Category = strings.TrimSpace(row.Category)
+		row.Email = strings.ToLower(strings.TrimSpace(row.Email))
+		if at := strings.Index(row.Email, "@"); at <= 0 || at == len(row.Email)-1 {
+			res[i].err = fmt.Errorf("bad email %q", row.Email)
+			continue
+		}
+		if _, ok := emails[row.Email]; ok {
+			res[i].skip = "duplicate email in roster"
+			continue
+		}
+		emails[row.Email] = struct{}{}
+		if row.Team != "" {
+			key := strings.ToLower(row.Team)
+			if teamCounts[key] >= teamSize {
+				res[i].err = fmt.Errorf("team %q has more than %d members", row.Team, teamSize)
+				continue
+			}
+			teamCounts[key]++
+		}
+	}
+	return res
+}
+
+// rosterTeam describes an existing team while a roster is being registered.
+type rosterTeam struct {
+	ref      *firestore.DocumentRef
+	numUsers int
+}
+
+// registerUser creates a Firebase Auth user (if needed) and a user doc for row.
+// If the row specifies a team, the user is added to the team from teams (keyed by
+// lowercase name), which is created if it doesn't already exist. If the user was
+// already registered, a non-empty reason for skipping the row is returned.
+func registerUser(ctx context.Context, client *firestore.Client, ac *auth.Client, comp string,
+	row *rosterRow, teams map[string]*rosterTeam, teamSize int) (skip string, err error) {
+	var uid string
+	if u, err := ac.GetUserByEmail(ctx, row.Email); err == nil {
+		uid = u.UID
+	} else if auth.IsUserNotFound(err) {
+		params := (&auth.UserToCreate{}).Email(row.Email).DisplayName(row.Name)
+		u, err := ac.CreateUser(ctx, params)
+		if err != nil {
+			return "", fmt.Errorf("creating auth user: %v", err)
+		}
+		log.Printf("Created auth user %v for %v", u.UID, row.Email)
+		uid = u.UID
+	} else {
+		return "", fmt.Errorf("getting auth user: %v", err)
+	}
+
+	userRef := client.Collection(db.UserCollectionPath(comp)).Doc(uid)
+	var existing db.User
+	if ok, err := getDocIfExists(ctx, userRef, &existing); err != nil {
+		return "", err
+	} else if ok {
+		return fmt.Sprintf("user %v already registered", uid), nil
+	}
+
+	userData := map[string]interface{}{"name": row.Name}
+	if row.Category != "" {
+		userData["category"] = row.Category
+	}
+	member := db.TeamUser{Name: row.Name, Climbs: make(map[string]db.ClimbState)}
+
+	if row.Team == "" {
+		// Create fails if the doc was created concurrently.
+		if _, err := userRef.Create(ctx, userData); err != nil {
+			return "", fmt.Errorf("creating user doc: %v", err)
+		}
+		return "", nil
+	}
+
+	key := strings.ToLower(row.Team)
+	if team, ok := teams[key]; ok {
+		if team.numUsers >= teamSize {
+			return "", fmt.Errorf("team %q is full", row.Team)
+		}
+		userData["team"] = team.ref.ID
+		batch := client.Batch()
+		batch.Create(userRef, userData)
+		batch.Update(team.ref, []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: member}})
+		if _, err := batch.Commit(ctx); err != nil {
+			return "", fmt.Errorf("adding to team: %v", err)
+		}
+		team.numUsers++
+		return "", nil
+	}
+
+	teamRef := client.Collection(db.TeamCollectionPath(comp)).NewDoc()
+	userData["team"] = teamRef.ID
+	code, err := commitWithInvite(ctx, client, comp, teamRef.ID, func(code string, batch *firestore.WriteBatch) {
+		batch.Create(teamRef, db.Team{
+			Name:   row.Team,
+			Invite: code,
+			Users:  map[string]db.TeamUser{uid: member},
+		})
+		batch.Create(userRef, userData)
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating team: %v", err)
+	}
+	log.Printf("Created team %v (%q) with invite %v for %v", teamRef.ID, row.Team, code, uid)
+	teams[key] = &rosterTeam{teamRef, 1}
+	return "", nil
+}
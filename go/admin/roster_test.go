@@ -0,0 +1,50 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAndCheckRoster(t *testing.T) {
+	const in = "Name,Email,Team_Name,Category\n" +
+		" Ann ,Ann@Example.com,Rock Stars,Open\n" +
+		"Bob,bob@example.com,rock stars,Open\n" +
+		"Cat,cat@example.com,Rock Stars,Youth\n" +
+		"Dan,ann@example.com,,\n" +
+		"Eve,not-an-email,,\n" +
+		",fay@example.com,,\n" +
+		"Gus,gus@example.com,,Youth\n"
+
+	rows, err := readRoster(strings.NewReader(in))
+	if err != nil {
+		t.Fatal("readRoster failed: ", err)
+	}
+	res := checkRoster(rows, 2)
+
+	type result struct {
+		row  rosterRow
+		skip bool
+		err  bool
+	}
+	var got []result
+	for i, r := range res {
+		got = append(got, result{rows[i], r.skip != "", r.err != nil})
+	}
+	want := []result{
+		{rosterRow{"Ann", "ann@example.com", "Rock Stars", "Open"}, false, false},
+		{rosterRow{"Bob", "bob@example.com", "rock stars", "Open"}, false, false},
+		{rosterRow{"Cat", "cat@example.com", "Rock Stars", "Youth"}, false, true}, // team full
+		{rosterRow{"Dan", "ann@example.com", "", ""}, true, false},                // dupe email
+		{rosterRow{"Eve", "not-an-email", "", ""}, false, true},                   // bad email
+		{rosterRow{"", "fay@example.com", "", ""}, false, true},                   // no name
+		{rosterRow{"Gus", "gus@example.com", "", "Youth"}, false, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got:\n%+v\nwant:\n%+v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// dataFormat describes the format of an uploaded document containing both areas and routes.
+type dataFormat string
+
+const (
+	jsonFormat dataFormat = "JSON"
+	yamlFormat dataFormat = "YAML"
+)
+
+// getDataFormat returns the format of the uploaded file described by fh.
+// The filename's extension is checked first, followed by its content type.
+func getDataFormat(fh *multipart.FileHeader) (dataFormat, error) {
+	switch strings.ToLower(filepath.Ext(fh.Filename)) {
+	case ".json":
+		return jsonFormat, nil
+	case ".yaml", ".yml":
+		return yamlFormat, nil
+	}
+
+	ct, _, _ := mime.ParseMediaType(fh.Header.Get("Content-Type"))
+	switch ct {
+	case "application/json", "text/json":
+		return jsonFormat, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return yamlFormat, nil
+	}
+
+	return "", fmt.Errorf("unable to determine format of %q (%q)", fh.Filename, ct)
+}
+
+// readData reads a document in the supplied format from r and returns its areas and routes.
+// The document should have the same structure as db.SortedData, i.e. a list of areas
+// that each contain a list of routes.
+func readData(r io.Reader, format dataFormat) ([]db.Area, []db.Route, error) {
+	var sd db.SortedData
+	switch format {
+	case jsonFormat:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&sd); err != nil {
+			return nil, nil, err
+		}
+	case yamlFormat:
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := yaml.UnmarshalStrict(b, &sd); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	if len(sd.Areas) == 0 {
+		return nil, nil, errors.New("no areas defined")
+	}
+
+	// Check that all required fields were supplied and that routes didn't try to
+	// place themselves in other areas.
+	for i, a := range sd.Areas {
+		if a.ID == "" {
+			return nil, nil, fmt.Errorf("area %d (%q) is missing ID", i, a.Name)
+		}
+		for j, rt := range a.Routes {
+			if rt.ID == "" {
+				return nil, nil, fmt.Errorf("route %d (%q) in area %q is missing ID", j, rt.Name, a.ID)
+			}
+			if rt.Area != "" && rt.Area != a.ID {
+				return nil, nil, fmt.Errorf("route %q in area %q specifies area %q", rt.ID, a.ID, rt.Area)
+			}
+		}
+	}
+
+	areas, routes := sd.Split()
+	return areas, routes, nil
+}
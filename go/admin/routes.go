@@ -6,11 +6,9 @@ package admin
 
 import (
 	"context"
-	"encoding/csv"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 
 	"cloud.google.com/go/firestore"
 
@@ -18,65 +16,144 @@ import (
 )
 
 // handlePostRoutes handles a "routes" POST request.
-// It reads uploaded CSV files from w and inserts data into Cloud Firestore.
-func handlePostRoutes(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	// Read supplied areas.
-	areasFile, _, err := r.FormFile("areas")
-	if err != nil {
-		http.Error(w, "Area data not supplied", http.StatusBadRequest)
-		return
-	}
-	areas, err := readAreas(areasFile)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed reading area data: %v", err), http.StatusBadRequest)
-		return
-	}
+// It reads uploaded area and route data from w and inserts it into Cloud Firestore.
+// The data is either supplied as separate "areas" and "routes" CSV files or as
+// a single JSON or YAML "data" file matching db.SortedData.
+func handlePostRoutes(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var areas []db.Area
+	var routes []db.Route
 
-	// Read supplied routes.
-	routesFile, _, err := r.FormFile("routes")
-	if err != nil {
-		http.Error(w, "Route data not supplied", http.StatusBadRequest)
-		return
-	}
-	routes, err := readRoutes(routesFile)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed reading route data: %v", err), http.StatusBadRequest)
+	if dataFile, dataHeader, err := r.FormFile("data"); err == nil {
+		// Read the supplied combined document.
+		format, err := getDataFormat(dataHeader)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if areas, routes, err = readData(dataFile, format); err != nil {
+			http.Error(w, loc.T("Failed reading %v data: %v", format, err), http.StatusBadRequest)
+			return
+		}
+	} else if err != http.ErrMissingFile {
+		http.Error(w, loc.T("Failed getting data file: %v", err), http.StatusBadRequest)
 		return
+	} else {
+		// Read supplied areas.
+		areasFile, _, err := r.FormFile("areas")
+		if err != nil {
+			http.Error(w, loc.T("Area data not supplied"), http.StatusBadRequest)
+			return
+		}
+		if areas, err = readAreas(areasFile); err != nil {
+			http.Error(w, loc.T("Failed reading area data: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Read supplied routes.
+		routesFile, _, err := r.FormFile("routes")
+		if err != nil {
+			http.Error(w, loc.T("Route data not supplied"), http.StatusBadRequest)
+			return
+		}
+		if routes, err = readRoutes(routesFile); err != nil {
+			http.Error(w, loc.T("Failed reading route data: %v", err), http.StatusBadRequest)
+			return
+		}
 	}
 
 	// Generate documents and write to Cloud Firestore.
 	sd, err := db.NewSortedData(areas, routes)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed sorting data: %v", err), http.StatusBadRequest)
+		http.Error(w, loc.T("Failed sorting data: %v", err), http.StatusBadRequest)
 		return
 	}
-	if _, err := client.Doc(db.SortedDataDocPath).Set(ctx, sd); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing to %v: %v", db.SortedDataDocPath, err),
+	if _, err := client.Doc(db.SortedDataDocPath(comp)).Set(ctx, sd); err != nil {
+		http.Error(w, loc.T("Failed writing to %v: %v", db.SortedDataDocPath(comp), err),
 			http.StatusInternalServerError)
 		return
 	}
 
-	if _, err := client.Doc(db.IndexedDataDocPath).Set(ctx, db.NewIndexedData(areas, routes)); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing to %v: %v", db.IndexedDataDocPath, err),
+	if _, err := client.Doc(db.IndexedDataDocPath(comp)).Set(ctx, db.NewIndexedData(areas, routes)); err != nil {
+		http.Error(w, loc.T("Failed writing to %v: %v", db.IndexedDataDocPath(comp), err),
 			http.StatusInternalServerError)
 		return
 	}
 
-	fmt.Fprintf(w, "Wrote %d area(s) and %d route(s)", len(areas), len(routes))
+	fmt.Fprint(w, loc.T("Wrote %d area(s) and %d route(s)", len(areas), len(routes)))
+}
+
+// areaCols describes the columns used in area CSV data, in the order in which they're written.
+var areaCols = []csvColumn{
+	{name: "id"},
+	{name: "name"},
+	{name: "mpid", aliases: []string{"mp_id"}, optional: true},
+}
+
+// areaDests returns a map from area CSV column names to the corresponding fields in a.
+// Unknown columns are preserved in a.Extra.
+func areaDests(a *db.Area) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         &a.ID,
+		"name":       &a.Name,
+		"mpid":       &a.MPID,
+		extraColsKey: &a.Extra,
+	}
+}
+
+// routeCols describes the columns used in route CSV data, in the order in which they're written.
+var routeCols = []csvColumn{
+	{name: "id"},
+	{name: "name"},
+	{name: "area"},
+	{name: "grade"},
+	{name: "lead", optional: true},
+	{name: "tr", aliases: []string{"toprope", "top_rope"}, optional: true},
+	{name: "flash", optional: true},
+	{name: "top", optional: true},
+	{name: "zone", optional: true},
+	{name: "mpid", aliases: []string{"mp_id"}, optional: true},
+	{name: "height", optional: true},
+	{name: "setter", optional: true},
+	{name: "color", aliases: []string{"colour"}, optional: true},
+	{name: "type", optional: true},
+	{name: "pitches", optional: true},
+	{name: "description", aliases: []string{"desc"}, optional: true},
+	{name: "verify", optional: true},
+}
+
+// routeDests returns a map from route CSV column names to the corresponding fields in rt.
+// Unknown columns are preserved in rt.Extra.
+func routeDests(rt *db.Route) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          &rt.ID,
+		"name":        &rt.Name,
+		"area":        &rt.Area,
+		"grade":       &rt.Grade,
+		"lead":        &rt.Lead,
+		"tr":          &rt.TR,
+		"flash":       &rt.Flash,
+		"top":         &rt.Top,
+		"zone":        &rt.Zone,
+		"mpid":        &rt.MPID,
+		"height":      &rt.Height,
+		"setter":      &rt.Setter,
+		"color":       &rt.Color,
+		"type":        (*string)(&rt.Type),
+		"pitches":     &rt.Pitches,
+		"description": &rt.Description,
+		"verify":      &rt.Verify,
+		extraColsKey:  &rt.Extra,
+	}
 }
 
 // readAreas reads and returns areas in CSV format from r.
-// The input must begin with a row specifying "id", "name", and "mpid" columns.
+// The input must begin with a row specifying the columns in areaCols.
 func readAreas(r io.Reader) ([]db.Area, error) {
 	var areas []db.Area
-	if err := readCSV(r, func() map[string]interface{} {
+	if err := readCSV(r, areaCols, preserveUnknown, func() map[string]interface{} {
 		areas = append(areas, db.Area{})
-		a := &areas[len(areas)-1]
-		return map[string]interface{}{
-			"id":   &a.ID,
-			"name": &a.Name,
-			"mpid": &a.MPID,
-		}
+		return areaDests(&areas[len(areas)-1])
 	}); err != nil {
 		return nil, err
 	}
@@ -84,86 +161,25 @@ func readAreas(r io.Reader) ([]db.Area, error) {
 }
 
 // readRoutes reads and returns routes in CSV format from r.
-// The input must begin with a row specifying "id", "name", "area", "grade",
-// "lead", "tr", and "mpid" columns.
+// The input must begin with a row specifying the columns in routeCols.
+// Each route must award points for roped climbs (lead and/or tr) or for
+// boulder problems (flash, top, and/or zone), but not both.
 func readRoutes(r io.Reader) ([]db.Route, error) {
 	var routes []db.Route
-	if err := readCSV(r, func() map[string]interface{} {
+	if err := readCSV(r, routeCols, preserveUnknown, func() map[string]interface{} {
 		routes = append(routes, db.Route{})
-		rt := &routes[len(routes)-1]
-		return map[string]interface{}{
-			"id":     &rt.ID,
-			"name":   &rt.Name,
-			"area":   &rt.Area,
-			"grade":  &rt.Grade,
-			"lead":   &rt.Lead,
-			"tr":     &rt.TR,
-			"mpid":   &rt.MPID,
-			"height": &rt.Height,
-		}
+		return routeDests(&routes[len(routes)-1])
 	}); err != nil {
 		return nil, err
 	}
-	return routes, nil
-}
-
-// rowDestFunc is passed to readCSV and returns a map from column name to
-// destination (either *string or *int) for data in a new row.
-type rowDestFunc func() map[string]interface{}
-
-// readCSV returns CSV data from r. The first row should contain column names.
-// f is invoked for each row and should return a map from column names to
-// destinations for their values.
-func readCSV(r io.Reader, f rowDestFunc) error {
-	cr := csv.NewReader(r)
-	head, err := cr.Read()
-	if err != nil {
-		return fmt.Errorf("failed to read header: %v", err)
-	}
-
-	for {
-		row, err := cr.Read()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return fmt.Errorf("failed reading row: %v", err)
-		}
-
-		// Iterate over columns and copy values to the appropriate destination.
-		dstMap := f()
-		for i, name := range head {
-			dst, ok := dstMap[name]
-			if !ok {
-				return fmt.Errorf("unknown or duplicate column %q in %q", name, row)
-			}
-
-			// Delete entries from the map as we go so we can detect duplicate
-			// columns and check that all expected columns were seen.
-			delete(dstMap, name)
-
-			switch td := dst.(type) {
-			case *string:
-				*td = row[i]
-			case *int:
-				if len(row[i]) == 0 {
-					*td = 0
-				} else if *td, err = strconv.Atoi(row[i]); err != nil {
-					return fmt.Errorf("failed to parse %q in %q: %v", row[i], row, err)
-				}
-			default:
-				return fmt.Errorf("unsupported type %T for column %q", td, name)
-			}
-		}
-
-		// If any columns remain in the map, they were missing from the row.
-		if len(dstMap) != 0 {
-			missing := make([]string, 0, len(dstMap))
-			for name := range dstMap {
-				missing = append(missing, name)
-			}
-			return fmt.Errorf("missing column(s) %q", missing)
+	for _, rt := range routes {
+		roped := rt.Lead != 0 || rt.TR != 0
+		boulder := rt.Flash != 0 || rt.Top != 0 || rt.Zone != 0
+		if !roped && !boulder {
+			return nil, fmt.Errorf("route %q has no lead, tr, flash, top, or zone points", rt.ID)
+		} else if roped && boulder {
+			return nil, fmt.Errorf("route %q has both roped and boulder points", rt.ID)
 		}
 	}
-
-	return nil
+	return routes, nil
 }
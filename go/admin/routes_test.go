@@ -5,6 +5,7 @@
 package admin
 
 import (
+	"bytes"
 	"reflect"
 	"strings"
 	"testing"
@@ -22,11 +23,15 @@ func TestReadAreas(t *testing.T) {
 			{ID: "a2", Name: "A2", MPID: ""}, // mpid is optional
 			{ID: "a3", Name: "A3", MPID: "456"},
 		}},
-		{"", nil},                                  // empty data, i.e. no header row
-		{"id,name,mpid\na1,123\n", nil},            // missing name column in data
-		{"id,mpid\na1,123\n", nil},                 // missing name column in header/data
-		{"id,name,mpid,id\na1,A1,123,a1\n", nil},   // duplicated id column
-		{"id,name,mpid,abc\na1,A1,123,def\n", nil}, // extra 'abc' column
+		{"", nil},                                // empty data, i.e. no header row
+		{"id,name,mpid\na1,123\n", nil},          // missing name column in data
+		{"id,mpid\na1,123\n", nil},               // missing name column in header/data
+		{"id,name,mpid,id\na1,A1,123,a1\n", nil}, // duplicated id column
+		{"id,name,mpid,abc\na1,A1,123,def\n", []db.Area{ // extra 'abc' column is preserved
+			{ID: "a1", Name: "A1", MPID: "123", Extra: map[string]string{"abc": "def"}},
+		}},
+		{"ID, Name\na1,A1\n", []db.Area{{ID: "a1", Name: "A1"}}}, // mpid omitted, other names differ in case
+		{"id,name,mp_id,mpid\na1,A1,123,123\n", nil},             // duplicate mpid column via alias
 	} {
 		if as, err := readAreas(strings.NewReader(tc.in)); err != nil {
 			if tc.out != nil {
@@ -59,11 +64,39 @@ func TestReadRoutes(t *testing.T) {
 			},
 		},
 		{"", nil}, // empty data, i.e. no header row
-		{"id,name,area,grade,lead,tr,mpid\nr1,R1,a1,5.8,10,123\n", nil},           // missing tr column in data
-		{"id,name,area,grade,lead,mpid\nr1,R1,a1,5.8,10,123\n", nil},              // missing tr column in header/data
-		{"id,name,area,grade,lead,tr,mpid,id\nr1,R1,a1,5.8,10,5,123,r1\n", nil},   // duplicated id column
-		{"id,name,area,grade,lead,tr,mpid,abc\nr1,R1,a1,5.8,10,5,123,def\n", nil}, // extra 'abc' column
-		{"id,name,area,grade,lead,tr,mpid\nr1,R1,a1,5.8,10,a,123\n", nil},         // unparseable tr value
+		{"id,name,area,grade,lead,tr,mpid\nr1,R1,a1,5.8,10,123\n", nil}, // missing tr column in data
+		{"id,name,area,grade,lead,mpid\nr1,R1,a1,5.8,10,123\n", []db.Route{ // no tr column
+			{ID: "r1", Name: "R1", Area: "a1", Grade: "5.8", Lead: 10, MPID: "123"},
+		}},
+		{"id,name,area,grade,lead,tr,mpid,id\nr1,R1,a1,5.8,10,5,123,r1\n", nil}, // duplicated id column
+		{"id,name,area,grade,lead,tr,mpid,abc\nr1,R1,a1,5.8,10,5,123,def\n", []db.Route{ // extra 'abc' column
+			{ID: "r1", Name: "R1", Area: "a1", Grade: "5.8", Lead: 10, TR: 5, MPID: "123",
+				Extra: map[string]string{"abc": "def"}},
+		}},
+		{
+			in: "id,name,area,grade,lead,tr,setter,color,type,pitches,description\n" +
+				"r1,R1,a1,5.8,10,5,Jane,red,sport,1,Crimpy start\n" +
+				"r2,R2,a1,5.7,8,4,,,trad,3,\n",
+			out: []db.Route{
+				{ID: "r1", Name: "R1", Area: "a1", Grade: "5.8", Lead: 10, TR: 5,
+					Setter: "Jane", Color: "red", Type: db.Sport, Pitches: 1, Description: "Crimpy start"},
+				{ID: "r2", Name: "R2", Area: "a1", Grade: "5.7", Lead: 8, TR: 4, Type: db.Trad, Pitches: 3},
+			},
+		},
+		{"id,name,area,grade,lead,tr,pitches\nr1,R1,a1,5.8,10,5,two\n", nil}, // unparseable pitches
+		{"id,name,area,grade,lead,tr,flash,top,zone\nb1,B1,a1,V3,,,10,8,3\n", []db.Route{
+			{ID: "b1", Name: "B1", Area: "a1", Grade: "V3", Flash: 10, Top: 8, Zone: 3},
+		}},
+		{"id,name,area,grade,type,flash,top\nb1,B1,a1,V5,boulder,12,10\n", []db.Route{ // no lead/tr columns
+			{ID: "b1", Name: "B1", Area: "a1", Grade: "V5", Type: db.Boulder, Flash: 12, Top: 10},
+		}},
+		{"id,name,area,grade,lead,tr\nr1,R1,a1,5.8,,\n", nil},          // no points
+		{"id,name,area,grade\nr1,R1,a1,5.8\n", nil},                    // no points columns
+		{"id,name,area,grade,lead,tr,top\nr1,R1,a1,5.8,10,5,8\n", nil}, // roped and boulder points
+		{"id,name,area,grade,lead,TopRope\nr1,R1,a1,5.8,10,5\n", []db.Route{ // alias, no mpid/height
+			{ID: "r1", Name: "R1", Area: "a1", Grade: "5.8", Lead: 10, TR: 5},
+		}},
+		{"id,name,area,grade,lead,tr,mpid\nr1,R1,a1,5.8,10,a,123\n", nil}, // unparseable tr value
 	} {
 		if rs, err := readRoutes(strings.NewReader(tc.in)); err != nil {
 			if tc.out != nil {
@@ -77,6 +110,110 @@ func TestReadRoutes(t *testing.T) {
 	}
 }
 
+func TestReadData(t *testing.T) {
+	areas := []db.Area{{ID: "a1", Name: "A1", MPID: "123"}, {ID: "a2", Name: "A2"}}
+	routes := []db.Route{
+		{ID: "r1", Name: "R1", Area: "a1", Grade: "5.8", Lead: 10, TR: 5, MPID: "456", Height: 80},
+		{ID: "r2", Name: "R2", Area: "a1", Grade: "5.9", Lead: 12, TR: 6},
+		{ID: "r3", Name: "R3", Area: "a2", Grade: "5.10a", Lead: 16, TR: 8},
+	}
+
+	for _, tc := range []struct {
+		desc   string     // human-readable description of test case
+		format dataFormat // input format
+		in     string     // input data
+		ok     bool       // whether areas and routes should be returned
+	}{
+		{"json", jsonFormat, `{"areas": [
+			{"id": "a1", "name": "A1", "mpId": "123", "routes": [
+				{"id": "r1", "name": "R1", "grade": "5.8", "lead": 10, "tr": 5, "mpId": "456", "height": 80},
+				{"id": "r2", "name": "R2", "grade": "5.9", "lead": 12, "tr": 6}
+			]},
+			{"id": "a2", "name": "A2", "routes": [
+				{"id": "r3", "name": "R3", "area": "a2", "grade": "5.10a", "lead": 16, "tr": 8}
+			]}
+		]}`, true},
+		{"yaml", yamlFormat, `
+areas:
+  - id: a1
+    name: A1
+    mpId: "123"
+    routes:
+      - {id: r1, name: R1, grade: "5.8", lead: 10, tr: 5, mpId: "456", height: 80}
+      - {id: r2, name: R2, grade: "5.9", lead: 12, tr: 6}
+  - id: a2
+    name: A2
+    routes:
+      - {id: r3, name: R3, area: a2, grade: 5.10a, lead: 16, tr: 8}
+`, true},
+		{"empty json", jsonFormat, ``, false},
+		{"no areas", yamlFormat, `areas: []`, false},
+		{"unknown json field", jsonFormat, `{"areas": [{"id": "a1", "name": "A1", "foo": 2}]}`, false},
+		{"unknown yaml field", yamlFormat, `areas: [{id: a1, name: A1, foo: 2}]`, false},
+		{"missing area id", jsonFormat, `{"areas": [{"name": "A1", "routes": [{"id": "r1"}]}]}`, false},
+		{"missing route id", jsonFormat, `{"areas": [{"id": "a1", "routes": [{"name": "R1"}]}]}`, false},
+		{"wrong route area", yamlFormat, `areas: [{id: a1, routes: [{id: r1, area: a2}]}]`, false},
+		{"bad points", yamlFormat, `areas: [{id: a1, routes: [{id: r1, lead: abc}]}]`, false},
+	} {
+		if as, rs, err := readData(strings.NewReader(tc.in), tc.format); err != nil {
+			if tc.ok {
+				t.Errorf("readData (%q) failed: %v", tc.desc, err)
+			}
+		} else if !tc.ok {
+			t.Errorf("readData (%q) unexpectedly succeeded with %+v, %+v", tc.desc, as, rs)
+		} else if !reflect.DeepEqual(as, areas) || !reflect.DeepEqual(rs, routes) {
+			t.Errorf("readData (%q) = %+v, %+v; want %+v, %+v", tc.desc, as, rs, areas, routes)
+		}
+	}
+}
+
+func TestWriteAreasAndRoutes(t *testing.T) {
+	const (
+		areasCSV = "id,name,mpid\n" +
+			"a1,A1,123\n" +
+			"a2,\"A2, with comma\",\n"
+		routesCSV = "id,name,area,grade,lead,tr,flash,top,zone,mpid,height,setter,color,type,pitches,description,verify\n" +
+			"r1,R1,a1,5.8,10,5,,,,123,80,Jane,red,sport,1,Crimpy start,\n" +
+			"b1,B1,a2,V4,,,12,10,4,,,,,boulder,,,true\n" +
+			"r3,\"R3 \"\"quoted\"\"\",a1,5.12d,20,10,,,,456,,,,trad,2,,\n"
+	)
+
+	areas, err := readAreas(strings.NewReader(areasCSV))
+	if err != nil {
+		t.Fatal("readAreas failed: ", err)
+	}
+	routes, err := readRoutes(strings.NewReader(routesCSV))
+	if err != nil {
+		t.Fatal("readRoutes failed: ", err)
+	}
+
+	// Pass the data through sortedData to make sure that it's split back out correctly.
+	sd, err := db.NewSortedData(areas, routes)
+	if err != nil {
+		t.Fatal("NewSortedData failed: ", err)
+	}
+	splitAreas, splitRoutes := sd.Split()
+
+	var b bytes.Buffer
+	if err := writeAreas(&b, splitAreas); err != nil {
+		t.Error("writeAreas failed: ", err)
+	} else if b.String() != areasCSV {
+		t.Errorf("writeAreas wrote %q; want %q", b.String(), areasCSV)
+	}
+
+	// Routes are grouped by area in sortedData.
+	const sortedRoutesCSV = "id,name,area,grade,lead,tr,flash,top,zone,mpid,height,setter,color,type,pitches,description,verify\n" +
+		"r1,R1,a1,5.8,10,5,,,,123,80,Jane,red,sport,1,Crimpy start,\n" +
+		"r3,\"R3 \"\"quoted\"\"\",a1,5.12d,20,10,,,,456,,,,trad,2,,\n" +
+		"b1,B1,a2,V4,,,12,10,4,,,,,boulder,,,true\n"
+	b.Reset()
+	if err := writeRoutes(&b, splitRoutes); err != nil {
+		t.Error("writeRoutes failed: ", err)
+	} else if b.String() != sortedRoutesCSV {
+		t.Errorf("writeRoutes wrote %q; want %q", b.String(), sortedRoutesCSV)
+	}
+}
+
 func TestNewSortedData(t *testing.T) {
 	a1 := db.Area{ID: "a1", Name: "A1"}
 	a2 := db.Area{ID: "a2", Name: "A2"}
@@ -103,6 +240,11 @@ func TestNewSortedData(t *testing.T) {
 			&db.SortedData{Areas: []db.Area{makeArea(a1, r1, r2), makeArea(a2, r3)}}},
 		{"route refers to nonexistent area", []db.Area{a1}, []db.Route{r1, r2, r3}, nil},
 		{"area doesn't have any routes", []db.Area{a1, a2}, []db.Route{r1, r2}, nil},
+		{"invalid route type", []db.Area{a1}, []db.Route{r1, {ID: "r4", Area: "a1", Type: "aid"}}, nil},
+		{"boulder with yds grade", []db.Area{a1}, []db.Route{r1, {ID: "b1", Area: "a1", Grade: "5.10a", Type: db.Boulder}}, nil},
+		{"boulder with v grade", []db.Area{a1}, []db.Route{{ID: "b1", Area: "a1", Grade: "V5-6", Type: db.Boulder}},
+			&db.SortedData{Areas: []db.Area{makeArea(a1, db.Route{ID: "b1", Grade: "V5-6", Type: db.Boulder})}}},
+		{"invalid pitch count", []db.Area{a1}, []db.Route{r1, {ID: "r4", Area: "a1", Pitches: -1}}, nil},
 	} {
 		if data, err := db.NewSortedData(tc.areas, tc.routes); err != nil {
 			if tc.out != nil {
@@ -0,0 +1,165 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+const (
+	defaultScoreboardRefresh = time.Minute      // default reload interval for public scoreboard
+	minScoreboardRefresh     = 10 * time.Second // minimum reload interval for public scoreboard
+)
+
+// HandleScoreboardRequest handles an HTTP request to the "Scoreboard" Cloud Function.
+// Unlike HandleRequest, no password is required: the scoreboard for the competition
+// named by the "competition" query parameter is served if the competition's config
+// permits it. The "view" parameter may be "teams" (the default) or "users", and the
+// "refresh" parameter specifies the page's reload interval in seconds (0 to disable).
+// The "lang" and "units" parameters are interpreted as described in newLocalizer.
+// Since the endpoint is public, internal errors are logged rather than returned.
+func HandleScoreboardRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	loc := newLocalizer(r)
+	if r.Method != http.MethodGet {
+		http.Error(w, loc.T("Bad method %q", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	comp := r.FormValue("competition")
+	if !db.ValidCompetitionID(comp) {
+		http.Error(w, loc.T("Bad competition %q", comp), http.StatusBadRequest)
+		return
+	}
+	view := r.FormValue("view")
+	if view == "" {
+		view = "teams"
+	} else if view != "teams" && view != "users" {
+		http.Error(w, loc.T("Bad view %q", view), http.StatusBadRequest)
+		return
+	}
+	refresh, err := getRefreshParam(r.FormValue("refresh"))
+	if err != nil {
+		http.Error(w, loc.T("Bad refresh: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	client, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT")) // set at deployment
+	if err != nil {
+		log.Printf("Failed creating Firestore client: %v", err)
+		http.Error(w, loc.T("Failed loading scoreboard"), http.StatusInternalServerError)
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		log.Printf("Failed getting %v config: %v", comp, err)
+		http.Error(w, loc.T("Failed loading scoreboard"), http.StatusInternalServerError)
+		return
+	}
+	// Use the same response for nonexistent and private competitions.
+	if !cfg.PublicScoreboard {
+		http.Error(w, loc.T("Scoreboard not available"), http.StatusNotFound)
+		return
+	}
+
+	teams, users, updated, err := getStandings(ctx, client, comp, maxStandingsAge)
+	if err != nil {
+		log.Printf("Failed getting %v standings: %v", comp, err)
+		http.Error(w, loc.T("Failed loading scoreboard"), http.StatusInternalServerError)
+		return
+	}
+	if cfg.AnonymizeScoreboard {
+		anonymizeScores(teams, users)
+	}
+
+	opts := scoresOptions{refresh: refresh, updated: updated, loc: loc}
+	if view == "teams" {
+		sortTeams(teams)
+		err = writeScores(w, teams, nil, opts)
+	} else {
+		err = writeScores(w, nil, users, opts)
+	}
+	if err != nil {
+		log.Printf("Failed writing %v scoreboard: %v", comp, err)
+		http.Error(w, loc.T("Failed loading scoreboard"), http.StatusInternalServerError)
+	}
+}
+
+// getRefreshParam parses a "refresh" parameter containing a number of seconds.
+// The default interval is returned if s is empty, and short intervals are clamped.
+func getRefreshParam(s string) (time.Duration, error) {
+	if s == "" {
+		return defaultScoreboardRefresh, nil
+	}
+	sec, err := strconv.Atoi(s)
+	if err != nil || sec < 0 {
+		return 0, fmt.Errorf("%q isn't a non-negative number of seconds", s)
+	}
+	refresh := time.Duration(sec) * time.Second
+	if refresh > 0 && refresh < minScoreboardRefresh {
+		refresh = minScoreboardRefresh
+	}
+	return refresh, nil
+}
+
+// handleScoreboard handles a "scoreboard" POST request.
+// It updates the config's public scoreboard settings using the "scoreboardPublic"
+// and "scoreboardAnonymize" checkbox parameters.
+func handleScoreboard(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	public := r.FormValue("scoreboardPublic") == "1"
+	anon := r.FormValue("scoreboardAnonymize") == "1"
+	if _, err := client.Doc(db.ConfigDocPath(comp)).Set(ctx, map[string]interface{}{
+		"publicScoreboard":    public,
+		"anonymizeScoreboard": anon,
+	}, firestore.MergeAll); err != nil {
+		http.Error(w, loc.T("Failed setting scoreboard state: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, loc.T("Set public scoreboard to %v (anonymized: %v)", public, anon))
+}
+
+// anonymizeScores replaces climbers' names in teams and users with their initials
+// and clears the climbers' route lists.
+func anonymizeScores(teams []teamSummary, users []userSummary) {
+	anon := func(u *userSummary) {
+		u.Name = getInitials(u.Name)
+		u.ClimbsDesc = ""
+		u.Climbs = nil
+	}
+	for i := range teams {
+		for j := range teams[i].Users {
+			anon(&teams[i].Users[j])
+		}
+	}
+	for i := range users {
+		anon(&users[i])
+	}
+}
+
+// getInitials returns the initials of the supplied name, e.g. "J. S." for "Jane Smith".
+func getInitials(name string) string {
+	var parts []string
+	for _, word := range strings.Fields(name) {
+		ch, _ := utf8.DecodeRuneInString(word)
+		parts = append(parts, string(unicode.ToUpper(ch))+".")
+	}
+	if len(parts) == 0 {
+		return "?"
+	}
+	return strings.Join(parts, " ")
+}
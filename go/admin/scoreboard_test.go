@@ -0,0 +1,73 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestGetInitials(t *testing.T) {
+	for _, tc := range []struct {
+		name, want string
+	}{
+		{"Jane Smith", "J. S."},
+		{"  ana   maría lópez ", "A. M. L."},
+		{"Étienne", "É."},
+		{"", "?"},
+	} {
+		if got := getInitials(tc.name); got != tc.want {
+			t.Errorf("getInitials(%q) = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAnonymizeScores(t *testing.T) {
+	climbs := map[string]db.ClimbState{"r1": db.Lead}
+	teams := []teamSummary{{"Team A", 10, 1, 50, 0, []userSummary{
+		{Name: "Jane Smith", Team: "Team A", Score: 10, ClimbsDesc: "Route 1 (L)", Climbs: climbs},
+	}}}
+	users := []userSummary{
+		{Name: "Jane Smith", Team: "Team A", Score: 10, ClimbsDesc: "Route 1 (L)", Climbs: climbs},
+	}
+	anonymizeScores(teams, users)
+
+	wantUser := userSummary{Name: "J. S.", Team: "Team A", Score: 10}
+	if want := []teamSummary{{"Team A", 10, 1, 50, 0, []userSummary{wantUser}}}; !reflect.DeepEqual(teams, want) {
+		t.Errorf("anonymizeScores produced teams %+v; want %+v", teams, want)
+	}
+	if want := []userSummary{wantUser}; !reflect.DeepEqual(users, want) {
+		t.Errorf("anonymizeScores produced users %+v; want %+v", users, want)
+	}
+}
+
+func TestGetRefreshParam(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", defaultScoreboardRefresh, true},
+		{"0", 0, true},
+		{"1", minScoreboardRefresh, true},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, false},
+		{"abc", 0, false},
+	} {
+		got, err := getRefreshParam(tc.s)
+		if !tc.ok {
+			if err == nil {
+				t.Errorf("getRefreshParam(%q) unexpectedly succeeded", tc.s)
+			}
+		} else if err != nil {
+			t.Errorf("getRefreshParam(%q) failed: %v", tc.s, err)
+		} else if got != tc.want {
+			t.Errorf("getRefreshParam(%q) = %v; want %v", tc.s, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// climbStateLabels contains human-readable English descriptions of climb states.
+var climbStateLabels = map[db.ClimbState]string{
+	db.Lead:    "Lead",
+	db.TopRope: "Top-rope",
+	db.Flash:   "Flash",
+	db.Top:     "Top",
+	db.Zone:    "Zone",
+}
+
+// handleScorecard handles a "scorecard" POST request.
+// It writes an HTML document listing the climbs of the user named by the "scorecardUser"
+// parameter or of each member of the team identified by the "scorecardTeam" parameter
+// (either a team ID or an invite code), grouped by area.
+func handleScorecard(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	uid := strings.TrimSpace(r.FormValue("scorecardUser"))
+	teamID := strings.TrimSpace(r.FormValue("scorecardTeam"))
+	if (uid == "") == (teamID == "") {
+		http.Error(w, loc.T("Exactly one of user or team must be supplied"), http.StatusBadRequest)
+		return
+	}
+
+	var sorted db.SortedData
+	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+		http.Error(w, loc.T("Failed getting sorted data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var title string
+	var cards []*scorecard
+	if uid != "" {
+		cloc, err := findClimbs(ctx, client, comp, uid, "", loc)
+		if err != nil {
+			writeClimbsError(w, loc, err)
+			return
+		}
+		var teamName string
+		if cloc.team != "" {
+			_, team, err := findTeam(ctx, client, comp, cloc.team)
+			if err != nil {
+				writeTeamError(w, loc, err)
+				return
+			}
+			teamName = team.Name
+		}
+		verifs, err := getUserVerifications(ctx, client, comp, uid)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		title = cloc.name
+		sc := newScorecard(uid, cloc.name, teamName, cloc.climbs, sorted.Areas, verifs)
+		sc.Left = cloc.left
+		cards = append(cards, sc)
+	} else {
+		_, team, err := findTeam(ctx, client, comp, teamID)
+		if err != nil {
+			writeTeamError(w, loc, err)
+			return
+		}
+		title = team.Name
+		for uid, tu := range team.Users {
+			verifs, err := getUserVerifications(ctx, client, comp, uid)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sc := newScorecard(uid, tu.Name, team.Name, tu.Climbs, sorted.Areas, verifs)
+			sc.Left = tu.Left
+			cards = append(cards, sc)
+		}
+		sort.Slice(cards, func(i, j int) bool { return cards[i].Name < cards[j].Name })
+	}
+
+	if err := writeScorecards(w, title, cards, teamID != "", loc); err != nil {
+		http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// getUserVerifications returns judges' verifications of user uid's climbs, keyed by route ID.
+// A nil map is returned if the user doesn't have any verifications.
+func getUserVerifications(ctx context.Context, client *firestore.Client, comp, uid string) (
+	map[string]db.Verification, error) {
+	var v db.UserVerifications
+	ref := client.Collection(db.VerificationCollectionPath(comp)).Doc(uid)
+	if _, err := getDocIfExists(ctx, ref, &v); err != nil {
+		return nil, fmt.Errorf("failed getting verifications: %v", err)
+	}
+	return v.Climbs, nil
+}
+
+// scorecardTotals contains totals computed the same way as in computeScore.
+type scorecardTotals struct {
+	Score     int
+	NumClimbs int
+	Height    int
+}
+
+func (t *scorecardTotals) add(o scorecardTotals) {
+	t.Score += o.Score
+	t.NumClimbs += o.NumClimbs
+	t.Height += o.Height
+}
+
+// scorecard lists a user's climbs.
+type scorecard struct {
+	UID   string
+	Name  string
+	Team  string // team name, or empty if the user isn't on a team
+	Left  bool   // user left the team after reporting climbs
+	Areas []scorecardArea
+	scorecardTotals
+}
+
+// scorecardArea lists a user's climbs within an area.
+type scorecardArea struct {
+	ID     string
+	Name   string
+	Climbs []scorecardClimb
+	scorecardTotals
+}
+
+// scorecardClimb describes a single climb within a scorecardArea.
+type scorecardClimb struct {
+	Route  string // route ID
+	Name   string
+	Grade  string
+	Style  string // English description, e.g. "Lead" or "Top-rope"
+	Points int
+	Height int // 0 if the climb isn't counted as an ascent
+	// Status contains the climb's db.VerificationStatus name if the route requires
+	// verification and the climb hasn't been approved.
+	Status string
+}
+
+// newScorecard returns a scorecard describing climbs, which are listed in the order
+// in which they appear in areas. verifs is keyed by route ID and may be nil.
+func newScorecard(uid, name, team string, climbs map[string]db.ClimbState, areas []db.Area,
+	verifs map[string]db.Verification) *scorecard {
+	sc := scorecard{UID: uid, Name: name, Team: team}
+	for _, a := range areas {
+		sa := scorecardArea{ID: a.ID, Name: a.Name}
+		for _, rt := range a.Routes {
+			state, ok := climbs[rt.ID]
+			if !ok || state == db.NotClimbed {
+				continue
+			}
+			verif := verifs[rt.ID]
+			sclimb := scorecardClimb{Route: rt.ID, Name: rt.Name, Grade: rt.Grade, Style: climbStateLabels[state]}
+			var ascent bool
+			sclimb.Points, ascent = climbPoints(rt, state, verif)
+			if ascent {
+				sclimb.Height = rt.Height
+				sa.NumClimbs++
+			}
+			if st := verif.GetStatus(state); rt.Verify && st != db.Approved {
+				sclimb.Status = st.String()
+			}
+			sa.Score += sclimb.Points
+			sa.Height += sclimb.Height
+			sa.Climbs = append(sa.Climbs, sclimb)
+		}
+		if len(sa.Climbs) > 0 {
+			sc.add(sa.scorecardTotals)
+			sc.Areas = append(sc.Areas, sa)
+		}
+	}
+	return &sc
+}
+
+// writeScorecards writes an HTML document containing cards to w, localized using loc.
+// If team is true, the cards' combined totals are also displayed.
+func writeScorecards(w io.Writer, title string, cards []*scorecard, team bool, loc *localizer) error {
+	tmpl, err := parseTableTemplate(scorecardTemplate, loc.funcs())
+	if err != nil {
+		return err
+	}
+	var total *scorecardTotals
+	if team {
+		total = &scorecardTotals{}
+		for _, sc := range cards {
+			total.add(sc.scorecardTotals)
+		}
+	}
+	return tmpl.Execute(w, struct {
+		SorttableJS template.JS
+		Title       string
+		Total       *scorecardTotals
+		Cards       []*scorecard
+	}{
+		SorttableJS: template.JS(sorttableJS),
+		Title:       title,
+		Total:       total,
+		Cards:       cards,
+	})
+}
+
+const scorecardTemplate = `
+<!DOCTYPE html>
+<html>
+  <head>
+    <title>{{T "Scorecard: %s" .Title}}</title>
+{{- template "tableHead" .}}
+    <style>
+      .card {
+        break-inside: avoid;
+        margin-bottom: 24px;
+      }
+      .area td {
+        background-color: #eee;
+        font-weight: bold;
+      }
+      .total td {
+        font-weight: bold;
+      }
+    </style>
+  </head>
+  <body>
+    <h1>{{.Title}}</h1>
+{{- with .Total}}
+    <p>{{T "Team total: %d points, %d climb(s), %s" .Score .NumClimbs (height .Height)}}</p>
+{{- end}}
+{{- range .Cards}}
+    <div class="card">
+      <h2 title="{{.UID}}">{{.Name}}{{if .Left}} {{T "(left team)"}}{{end}}</h2>
+{{- if .Team}}
+      <p>{{T "Team: %s" .Team}}</p>
+{{- end}}
+      <table>
+        <thead>
+          <tr>
+            <th>{{T "Route"}}</th>
+            <th>{{T "Grade"}}</th>
+            <th>{{T "Style"}}</th>
+            <th>{{T "Points"}}</th>
+            <th>{{T "Height"}}</th>
+          </tr>
+        </thead>
+        <tbody>
+{{- range .Areas}}
+          <tr class="area">
+            <td colspan="3" title="{{.ID}}">{{.Name}}</td>
+            <td class="num">{{.Score}}</td>
+            <td class="num">{{height .Height}}</td>
+          </tr>
+{{- range .Climbs}}
+          <tr>
+            <td title="{{.Route}}">{{.Name}}</td>
+            <td>{{.Grade}}</td>
+            <td>{{T .Style}}{{if .Status}} ({{T .Status}}){{end}}</td>
+            <td class="num">{{.Points}}</td>
+            <td class="num">{{height .Height}}</td>
+          </tr>
+{{- end}}
+{{- end}}
+          <tr class="total">
+            <td colspan="3">{{T "Total (%d climb(s))" .NumClimbs}}</td>
+            <td class="num">{{.Score}}</td>
+            <td class="num">{{height .Height}}</td>
+          </tr>
+        </tbody>
+      </table>
+    </div>
+{{- end}}
+  </body>
+</html>
+`
@@ -0,0 +1,79 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestNewScorecard(t *testing.T) {
+	routes := make(map[string]db.Route)
+	for _, a := range statsAreas {
+		for _, rt := range a.Routes {
+			routes[rt.ID] = rt
+		}
+	}
+
+	for _, tc := range []struct {
+		climbs map[string]db.ClimbState
+		verifs map[string]db.Verification
+		want   []scorecardArea
+	}{
+		{nil, nil, nil},
+		{
+			map[string]db.ClimbState{"b1": db.Zone, "r1": db.Lead, "r2": db.TopRope},
+			nil,
+			[]scorecardArea{
+				{ID: "a1", Name: "Area 1", Climbs: []scorecardClimb{
+					{Route: "r1", Name: "Route 1", Grade: "5.10a", Style: "Lead", Points: 10, Height: 60},
+					{Route: "r2", Name: "Route 2", Grade: "5.12a", Style: "Top-rope", Status: "pending"},
+				}, scorecardTotals: scorecardTotals{Score: 10, NumClimbs: 1, Height: 60}},
+				{ID: "a2", Name: "Area 2", Climbs: []scorecardClimb{
+					{Route: "b1", Name: "Boulder 1", Grade: "V2", Style: "Zone", Points: 4},
+				}, scorecardTotals: scorecardTotals{Score: 4}},
+			},
+		},
+		{
+			map[string]db.ClimbState{"r2": db.Lead, "b1": db.NotClimbed},
+			map[string]db.Verification{"r2": {State: db.Lead, Status: db.Approved}},
+			[]scorecardArea{
+				{ID: "a1", Name: "Area 1", Climbs: []scorecardClimb{
+					{Route: "r2", Name: "Route 2", Grade: "5.12a", Style: "Lead", Points: 30, Height: 80},
+				}, scorecardTotals: scorecardTotals{Score: 30, NumClimbs: 1, Height: 80}},
+			},
+		},
+	} {
+		sc := newScorecard("u1", "User 1", "Team", tc.climbs, statsAreas, tc.verifs)
+		if !reflect.DeepEqual(sc.Areas, tc.want) {
+			t.Errorf("newScorecard(%v, %v) returned areas %+v; want %+v", tc.climbs, tc.verifs, sc.Areas, tc.want)
+		}
+		var want scorecardTotals
+		want.Score, want.NumClimbs, want.Height = computeScore(tc.climbs, routes, tc.verifs)
+		if sc.scorecardTotals != want {
+			t.Errorf("newScorecard(%v, %v) returned totals %+v; computeScore returned %+v",
+				tc.climbs, tc.verifs, sc.scorecardTotals, want)
+		}
+	}
+}
+
+func TestWriteScorecards(t *testing.T) {
+	cards := []*scorecard{
+		newScorecard("u1", "User 1", "Team", statsUsers[0].Climbs, statsAreas, statsVerifs["u1"].Climbs),
+		newScorecard("u2", "User 2", "Team", statsUsers[1].Climbs, statsAreas, statsVerifs["u2"].Climbs),
+	}
+	var b bytes.Buffer
+	if err := writeScorecards(&b, "Team", cards, true, defaultLocalizer); err != nil {
+		t.Fatal("writeScorecards failed: ", err)
+	}
+	for _, s := range []string{"Team total: 61 points, 4 climb(s)", "Top-rope (rejected)"} {
+		if !bytes.Contains(b.Bytes(), []byte(s)) {
+			t.Errorf("writeScorecards output doesn't contain %q", s)
+		}
+	}
+}
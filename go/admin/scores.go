@@ -14,94 +14,127 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
-	"google.golang.org/api/iterator"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/derat/ascenso/go/db"
 )
 
 // handlePostScoresTeams handles a "scoresTeams" POST request.
 // It reads teams' scores from Cloud Firestore and writes an HTML scoreboard document to w.
-func handlePostScoresTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	teams, _, err := getScores(ctx, client)
+func handlePostScoresTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	teams, _, updated, err := loadScores(ctx, r, client, comp)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed loading scores: %v", err), http.StatusInternalServerError)
+		http.Error(w, loc.T("Failed loading scores: %v", err), loadErrorStatus(err))
 		return
 	}
-	sort.Slice(teams, func(i, j int) bool { return teams[i].Score > teams[j].Score })
-	if err := writeScores(w, teams, nil); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing template: %v", err), http.StatusInternalServerError)
+	sortTeams(teams)
+	if err := writeScores(w, teams, nil, scoresOptions{updated: updated, loc: loc}); err != nil {
+		http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
 		return
 	}
 }
 
 // handlePostScoresUsers handles a "scoresUsers" POST request.
 // It reads users' scores from Cloud Firestore and writes an HTML scoreboard document to w.
-func handlePostScoresUsers(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	_, users, err := getScores(ctx, client)
+func handlePostScoresUsers(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	_, users, updated, err := loadScores(ctx, r, client, comp)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed loading scores: %v", err), http.StatusInternalServerError)
+		http.Error(w, loc.T("Failed loading scores: %v", err), loadErrorStatus(err))
 		return
 	}
-	if err := writeScores(w, nil, users); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing template: %v", err), http.StatusInternalServerError)
+	if err := writeScores(w, nil, users, scoresOptions{updated: updated, loc: loc}); err != nil {
+		http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
 		return
 	}
 }
 
 // handlePostScoresTeamsCSV handles a "scoresTeamsCsv" POST request.
-func handlePostScoresTeamsCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	teams, _, err := getScores(ctx, client)
+func handlePostScoresTeamsCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	teams, _, updated, err := loadScores(ctx, r, client, comp)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed loading scores: %v", err), http.StatusInternalServerError)
+		http.Error(w, loc.T("Failed loading scores: %v", err), loadErrorStatus(err))
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+	recs := makeTeamRecords(teams, cfg.GetTeamSize())
+
+	setCSVHeaders(w.Header(), "teams.csv")
+	setLastModified(w.Header(), updated)
+	if err := csv.NewWriter(w).WriteAll(recs); err != nil {
+		http.Error(w, loc.T("Failed writing teams: %v", err), http.StatusInternalServerError)
+	}
+}
 
-	recs := [][]string{{"team", "climber_1", "climber_2", "score", "climbs", "height"}}
+// makeTeamRecords returns CSV records (including a header) describing teams.
+// A "climber_N" column is included for each member of the largest team, and at
+// least teamSize climber columns are included.
+func makeTeamRecords(teams []teamSummary, teamSize int) [][]string {
+	n := teamSize
 	for _, team := range teams {
-		rec := []string{team.Name}
-		if len(team.Users) > 0 {
-			rec = append(rec, team.Users[0].Name)
-		} else {
-			rec = append(rec, "")
-		}
-		if len(team.Users) > 1 {
-			rec = append(rec, team.Users[1].Name)
-		} else {
-			rec = append(rec, "")
+		if len(team.Users) > n {
+			n = len(team.Users)
 		}
-		rec = append(rec, strconv.Itoa(team.Score), strconv.Itoa(team.NumClimbs), strconv.Itoa(team.Height))
+	}
 
-		recs = append(recs, rec)
+	header := []string{"team"}
+	for i := 1; i <= n; i++ {
+		header = append(header, fmt.Sprintf("climber_%d", i))
 	}
+	recs := [][]string{append(header, "score", "climbs", "height")}
 
-	setCSVHeaders(w.Header(), "teams.csv")
-	if err := csv.NewWriter(w).WriteAll(recs); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing teams: %v", err), http.StatusInternalServerError)
+	for _, team := range teams {
+		rec := []string{team.Name}
+		for i := 0; i < n; i++ {
+			if i < len(team.Users) {
+				rec = append(rec, team.Users[i].Name)
+			} else {
+				rec = append(rec, "")
+			}
+		}
+		rec = append(rec, strconv.Itoa(team.Score), strconv.Itoa(team.NumClimbs), strconv.Itoa(team.Height))
+		recs = append(recs, rec)
 	}
+	return recs
 }
 
 // handlePostScoresUsersCSV handles a "scoresUsersCsv" POST request.
-func handlePostScoresUsersCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client) {
-	_, users, err := getScores(ctx, client)
+func handlePostScoresUsersCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	_, users, updated, err := loadScores(ctx, r, client, comp)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed loading scores: %v", err), http.StatusInternalServerError)
+		http.Error(w, loc.T("Failed loading scores: %v", err), loadErrorStatus(err))
 		return
 	}
 
+	recs := makeUserRecords(users)
+
+	setCSVHeaders(w.Header(), "users.csv")
+	setLastModified(w.Header(), updated)
+	if err := csv.NewWriter(w).WriteAll(recs); err != nil {
+		http.Error(w, loc.T("Failed writing users: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// makeUserRecords returns CSV records (including a header) describing users.
+func makeUserRecords(users []userSummary) [][]string {
 	recs := [][]string{{"climber", "team", "score", "climbs", "height"}}
 	for _, u := range users {
 		recs = append(recs, []string{
 			u.Name, u.Team, strconv.Itoa(u.Score), strconv.Itoa(u.NumClimbs), strconv.Itoa(u.Height),
 		})
 	}
-
-	setCSVHeaders(w.Header(), "users.csv")
-	if err := csv.NewWriter(w).WriteAll(recs); err != nil {
-		http.Error(w, fmt.Sprintf("Failed writing users: %v", err), http.StatusInternalServerError)
-	}
+	return recs
 }
 
 // setCSVHeaders sets headers on h to indicate a CSV attachment with the given filename.
@@ -111,34 +144,62 @@ func setCSVHeaders(h http.Header, fn string) {
 	h.Set("Content-Disposition", "attachment; filename="+fn)
 }
 
-// getScores reads scores from Cloud Firestore and returns summarized data.
-func getScores(ctx context.Context, client *firestore.Client) ([]teamSummary, []userSummary, error) {
-	// First, load data so we can look up the points and heights for each route.
-	var indexed db.IndexedData
-	if err := db.GetDoc(ctx, client.Doc(db.IndexedDataDocPath), &indexed); err != nil {
-		return nil, nil, fmt.Errorf("failed getting indexed data: %v", err)
+// setLastModified sets a Last-Modified header on h reporting that scores were computed
+// at t. Nothing is done if t is zero.
+func setLastModified(h http.Header, t time.Time) {
+	if !t.IsZero() {
+		h.Set("Last-Modified", t.UTC().Format(http.TimeFormat))
 	}
+}
+
+// scoresData contains summarized scores returned by getScores along with the data
+// that was loaded to compute them.
+type scoresData struct {
+	teams  []teamSummary
+	users  []userSummary                   // sorted by descending score
+	sorted db.SortedData                   // areas and routes
+	verifs map[string]db.UserVerifications // keyed by user ID
+}
+
+// getScores reads competition comp's scores from Cloud Firestore and returns summarized data.
+// Route data, verifications, and teams are loaded concurrently, and all teams are read
+// via a single collection query rather than one request per team.
+func getScores(ctx context.Context, client *firestore.Client, comp string) (*scoresData, error) {
+	var indexed db.IndexedData
 	var sorted db.SortedData
-	if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath), &sorted); err != nil {
-		return nil, nil, fmt.Errorf("failed getting sorted data: %v", err)
+	var verifs map[string]db.UserVerifications
+	var teamDocs []db.Team
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		// Load data so we can look up the points and heights for each route.
+		return getDocs(gctx, client, map[string]interface{}{
+			db.IndexedDataDocPath(comp): &indexed,
+			db.SortedDataDocPath(comp):  &sorted,
+		})
+	})
+	g.Go(func() (err error) {
+		verifs, err = getVerifications(gctx, client, comp)
+		return err
+	})
+	g.Go(func() error {
+		if err := loadCollection(gctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+			var team db.Team
+			err := snap.DataTo(&team)
+			teamDocs = append(teamDocs, team)
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed loading teams: %v", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
-	// Iterate over all of the teams.
 	var teams []teamSummary
 	var users []userSummary
-	it := client.Collection(db.TeamCollectionPath).DocumentRefs(ctx)
-	for {
-		ref, err := it.Next()
-		if err == iterator.Done {
-			break
-		} else if err != nil {
-			return nil, nil, fmt.Errorf("failed getting team ref: %v", err)
-		}
-		var team db.Team
-		if err := db.GetDoc(ctx, ref, &team); err != nil {
-			return nil, nil, fmt.Errorf("failed getting team doc: %v", err)
-		}
-
+	for _, team := range teamDocs {
 		if len(team.Users) == 0 {
 			continue
 		}
@@ -146,48 +207,93 @@ func getScores(ctx context.Context, client *firestore.Client) ([]teamSummary, []
 		ts := teamSummary{Name: team.Name}
 
 		// Iterate over the team's members.
-		for _, u := range team.Users {
-			score, climbs, height := computeScore(u.Climbs, indexed.Routes)
+		for uid, u := range team.Users {
+			score, climbs, height := computeScore(u.Climbs, indexed.Routes, verifs[uid].Climbs)
+			attempts := countAttempts(u.Climbs, u.Attempts, indexed.Routes, verifs[uid].Climbs)
 			ts.Score += score
 			ts.NumClimbs += climbs
 			ts.Height += height
+			ts.Attempts += attempts
 			us := userSummary{
+				UID:        uid,
 				Name:       u.Name,
 				Team:       team.Name,
 				Score:      score,
 				NumClimbs:  climbs,
 				Height:     height,
+				Attempts:   attempts,
 				ClimbsDesc: makeClimbsDesc(u.Climbs, sorted.Areas),
+				Climbs:     u.Climbs,
 			}
 			ts.Users = append(ts.Users, us)
 			users = append(users, us)
 		}
 
-		// Sort the team's members by descending score and then alphabetically.
-		sort.Slice(ts.Users, func(i, j int) bool {
-			if si, sj := ts.Users[i].Score, ts.Users[j].Score; si != sj {
-				return si > sj
-			}
-			return ts.Users[i].Name < ts.Users[j].Name
-		})
+		// Sort the team's members by descending score, ascending attempts, and then alphabetically.
+		sortUsers(ts.Users)
 
 		teams = append(teams, ts)
 	}
 
-	// Sort the users by descending score and then alphabetically.
+	sortUsers(users)
+	return &scoresData{teams: teams, users: users, sorted: sorted, verifs: verifs}, nil
+}
+
+// getDocs fetches the docs keyed by path in docs using a single request and decodes
+// each into the corresponding value, which should be a pointer to a struct.
+// An error is returned if any of the docs don't exist.
+func getDocs(ctx context.Context, client *firestore.Client, docs map[string]interface{}) error {
+	refs := make([]*firestore.DocumentRef, 0, len(docs))
+	dsts := make([]interface{}, 0, len(docs))
+	for p, dst := range docs {
+		refs = append(refs, client.Doc(p))
+		dsts = append(dsts, dst)
+	}
+	// GetAll returns snapshots in the same order as refs.
+	snaps, err := client.GetAll(ctx, refs)
+	if err != nil {
+		return fmt.Errorf("failed getting docs: %v", err)
+	}
+	for i, snap := range snaps {
+		if !snap.Exists() {
+			return fmt.Errorf("%v doesn't exist", refs[i].Path)
+		}
+		if err := snap.DataTo(dsts[i]); err != nil {
+			return fmt.Errorf("failed decoding %v: %v", refs[i].Path, err)
+		}
+	}
+	return nil
+}
+
+// sortUsers sorts users by descending score, ascending boulder attempts, and then alphabetically.
+func sortUsers(users []userSummary) {
 	sort.Slice(users, func(i, j int) bool {
 		if si, sj := users[i].Score, users[j].Score; si != sj {
 			return si > sj
 		}
+		if ai, aj := users[i].Attempts, users[j].Attempts; ai != aj {
+			return ai < aj
+		}
 		return users[i].Name < users[j].Name
 	})
+}
 
-	return teams, users, nil
+// sortTeams sorts teams by descending score and then ascending boulder attempts.
+// The existing order of tied teams is preserved.
+func sortTeams(teams []teamSummary) {
+	sort.SliceStable(teams, func(i, j int) bool {
+		if si, sj := teams[i].Score, teams[j].Score; si != sj {
+			return si > sj
+		}
+		return teams[i].Attempts < teams[j].Attempts
+	})
 }
 
 // computeScore iterates over the supplied climbs and returns the user's total score, number of
-// climbs, and total height.
-func computeScore(climbs map[string]db.ClimbState, routes map[string]db.Route) (points, count, height int) {
+// climbs, and total height. Climbs of routes requiring verification are only counted if they
+// were approved in verifs, which is keyed by route ID and may be nil.
+func computeScore(climbs map[string]db.ClimbState, routes map[string]db.Route,
+	verifs map[string]db.Verification) (points, count, height int) {
 	if climbs == nil || routes == nil {
 		return 0, 0, 0
 	}
@@ -197,18 +303,56 @@ func computeScore(climbs map[string]db.ClimbState, routes map[string]db.Route) (
 		if !ok {
 			continue
 		}
-		if state == db.Lead {
-			points += rt.Lead
-			count++
-		} else if state == db.TopRope {
-			points += rt.TR
+		p, ascent := climbPoints(rt, state, verifs[id])
+		points += p
+		if ascent {
 			count++
+			height += rt.Height
 		}
-		height += rt.Height
 	}
 	return points, count, height
 }
 
+// climbPoints returns the points awarded for climbing rt in state and whether the climb
+// counts as an ascent. If rt requires verification, the climb is only counted if verif
+// approves it.
+func climbPoints(rt db.Route, state db.ClimbState, verif db.Verification) (points int, ascent bool) {
+	if rt.Verify && verif.GetStatus(state) != db.Approved {
+		return 0, false
+	}
+	switch state {
+	case db.Lead:
+		return rt.Lead, true
+	case db.TopRope:
+		return rt.TR, true
+	case db.Flash:
+		return rt.Flash, true
+	case db.Top:
+		return rt.Top, true
+	case db.Zone:
+		// Reaching the zone earns points but isn't an ascent.
+		return rt.Zone, false
+	}
+	return 0, false
+}
+
+// countAttempts returns the total number of attempts that a user made on the boulder
+// problems in climbs that count toward their score. attempts contains the user's recorded
+// attempts keyed by route ID and may be nil; see db.ClimbAttempts. verifs is used as
+// described in computeScore.
+func countAttempts(climbs map[string]db.ClimbState, attempts map[string]int,
+	routes map[string]db.Route, verifs map[string]db.Verification) int {
+	var total int
+	for id, state := range climbs {
+		rt, ok := routes[id]
+		if !ok || (rt.Verify && verifs[id].GetStatus(state) != db.Approved) {
+			continue
+		}
+		total += db.ClimbAttempts(state, attempts[id])
+	}
+	return total
+}
+
 // makeClimbsDesc generates a multiline list of a user's climbs.
 func makeClimbsDesc(climbs map[string]db.ClimbState, areas []db.Area) string {
 	var lines []string
@@ -220,6 +364,12 @@ func makeClimbsDesc(climbs map[string]db.ClimbState, areas []db.Area) string {
 					lines = append(lines, r.Name+" (L)")
 				case db.TopRope:
 					lines = append(lines, r.Name+" (TR)")
+				case db.Flash:
+					lines = append(lines, r.Name+" (F)")
+				case db.Top:
+					lines = append(lines, r.Name+" (T)")
+				case db.Zone:
+					lines = append(lines, r.Name+" (Z)")
 				}
 			}
 		}
@@ -233,92 +383,102 @@ type teamSummary struct {
 	Score     int
 	NumClimbs int
 	Height    int
+	Attempts  int // boulder attempts
 	Users     []userSummary
 }
 
 // userSummary describes an individual climber's performance.
 type userSummary struct {
+	UID        string // empty for archived scores
 	Name       string
 	Team       string // redundant, but used for per-user CSV
 	Score      int
 	NumClimbs  int
 	Height     int
-	ClimbsDesc string // multiline string for title attr
+	Attempts   int                      // boulder attempts
+	ClimbsDesc string                   // multiline string for title attr
+	Climbs     map[string]db.ClimbState // keyed by route ID
+}
+
+// scoresOptions contains optional settings for writeScores.
+type scoresOptions struct {
+	// refresh contains the interval at which the page should be reloaded.
+	// If zero, the page isn't reloaded.
+	refresh time.Duration
+	// updated contains the time at which the scores were computed.
+	// If non-zero, it's displayed above the scores.
+	updated time.Time
+	// loc is used to localize the document. If nil, defaultLocalizer is used.
+	loc *localizer
 }
 
 // writeScores writes an HTML document describing the scores in teams (if non-empty)
 // or users (otherwise) to w.
-func writeScores(w io.Writer, teams []teamSummary, users []userSummary) error {
-	tmpl, err := template.New("").Parse(strings.TrimLeft(scoresTemplate, "\n"))
+func writeScores(w io.Writer, teams []teamSummary, users []userSummary, opts scoresOptions) error {
+	loc := opts.loc
+	if loc == nil {
+		loc = defaultLocalizer
+	}
+	tmpl, err := parseTableTemplate(scoresTemplate, loc.funcs())
 	if err != nil {
 		return err
 	}
 	return tmpl.Execute(w, struct {
 		SorttableJS template.JS
+		RefreshSec  int
+		Updated     string
 		Teams       []teamSummary
 		Users       []userSummary
 	}{
 		SorttableJS: template.JS(sorttableJS),
+		RefreshSec:  int(opts.refresh / time.Second),
+		Updated:     formatUpdated(opts.updated, loc),
 		Teams:       teams,
 		Users:       users,
 	})
 }
 
+// formatUpdated formats t for display by writeScores using loc.
+// An empty string is returned if t is zero.
+func formatUpdated(t time.Time, loc *localizer) string {
+	if t.IsZero() {
+		return ""
+	}
+	return loc.T("Updated %s", t.UTC().Format("2006-01-02 15:04:05 MST"))
+}
+
 const scoresTemplate = `
 <!DOCTYPE html>
 <html>
   <head>
-    <title>Scores</title>
-    <style>
-      body {
-        font-family: Arial, Helvetica, sans-serif;
-        font-size: 14px;
-      }
-      table {
-        border: 1px solid #aaa;
-        border-collapse: collapse;
-      }
-      td, th {
-        border: 1px solid #aaa;
-        max-width: 20em;
-        padding: 2px 10px;
-        vertical-align: middle;
-      }
-      th {
-        background-color: #ddd;
-        font-weight: bold;
-        line-height: 1.5;
-        text-align: left;
-      }
-      .num {
-        text-align: right;
-      } 
-    </style>
-    <script>
-     {{.SorttableJS}}
-     // Make sorttable use slower locale-aware sorting.
-     sorttable.sort_alpha = function(a,b) { return a[0].localeCompare(b[0]); }
-    </script>
+    <title>{{T "Scores"}}</title>
+{{- if .RefreshSec}}
+    <meta http-equiv="refresh" content="{{.RefreshSec}}">
+{{- end}}
+{{- template "tableHead" .}}
   </head>
   <body>
+{{- if .Updated}}
+    <div class="updated">{{.Updated}}</div>
+{{- end}}
     <table class="sortable">
       <thead>
         <tr>
 {{- if .Teams}}
-          <th>Team</th>
-          <th>Score</th>
-          <th>Climbs</th>
-          <th>Height</th>
-          <th class="sorttable_nosort">Climber</th>
-          <th class="sorttable_nosort">Score</th>
-          <th class="sorttable_nosort">Climbs</th>
-          <th class="sorttable_nosort">Height</th>
+          <th>{{T "Team"}}</th>
+          <th>{{T "Score"}}</th>
+          <th>{{T "Climbs"}}</th>
+          <th>{{T "Height"}}</th>
+          <th class="sorttable_nosort">{{T "Climber"}}</th>
+          <th class="sorttable_nosort">{{T "Score"}}</th>
+          <th class="sorttable_nosort">{{T "Climbs"}}</th>
+          <th class="sorttable_nosort">{{T "Height"}}</th>
 {{- else}}
-          <th>Climber</th>
-          <th>Team</th>
-          <th>Score</th>
-          <th>Climbs</th>
-          <th>Height</th>
+          <th>{{T "Climber"}}</th>
+          <th>{{T "Team"}}</th>
+          <th>{{T "Score"}}</th>
+          <th>{{T "Climbs"}}</th>
+          <th>{{T "Height"}}</th>
 {{- end}}
         </tr>
       </thead>
@@ -329,7 +489,7 @@ const scoresTemplate = `
           <td>{{.Name}}</td>
           <td class="num">{{.Score}}</td>
           <td class="num">{{.NumClimbs}}</td>
-          <td class="num" sorttable_customkey="{{.Height}}">{{.Height}}'</td>
+          <td class="num" sorttable_customkey="{{.Height}}">{{height .Height}}</td>
           <td>
 {{- range .Users}}
             <span title="{{.ClimbsDesc}}">{{.Name}}</span><br>
@@ -347,7 +507,7 @@ const scoresTemplate = `
           </td>
           <td class="num">
 {{- range .Users}}
-            {{.Height}}'<br>
+            {{height .Height}}<br>
 {{- end}}
           </td>
         </tr>
@@ -359,7 +519,7 @@ const scoresTemplate = `
           <td>{{.Team}}</td>
           <td class="num">{{.Score}}</td>
           <td class="num">{{.NumClimbs}}</td>
-          <td class="num" sorttable_customkey="{{.Height}}">{{.Height}}'</td>
+          <td class="num" sorttable_customkey="{{.Height}}">{{height .Height}}</td>
         </tr>
 {{- end}}
 {{- end}}
@@ -369,6 +529,57 @@ const scoresTemplate = `
 </html>
 `
 
+// tableHeadTemplate defines a "tableHead" template containing style and script elements
+// shared by pages that display sortable tables. Its data must have a SorttableJS field.
+const tableHeadTemplate = `
+{{define "tableHead"}}
+    <style>
+      body {
+        font-family: Arial, Helvetica, sans-serif;
+        font-size: 14px;
+      }
+      table {
+        border: 1px solid #aaa;
+        border-collapse: collapse;
+      }
+      td, th {
+        border: 1px solid #aaa;
+        max-width: 20em;
+        padding: 2px 10px;
+        vertical-align: middle;
+      }
+      th {
+        background-color: #ddd;
+        font-weight: bold;
+        line-height: 1.5;
+        text-align: left;
+      }
+      .num {
+        text-align: right;
+      }
+      .updated {
+        color: #666;
+        margin-bottom: 8px;
+      }
+    </style>
+    <script>
+     {{.SorttableJS}}
+     // Make sorttable use slower locale-aware sorting.
+     sorttable.sort_alpha = function(a,b) { return a[0].localeCompare(b[0]); }
+    </script>
+{{- end}}
+`
+
+// parseTableTemplate parses the page template text along with tableHeadTemplate.
+// funcs may be nil.
+func parseTableTemplate(text string, funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(funcs).Parse(strings.TrimLeft(text, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.Parse(tableHeadTemplate)
+}
+
 // Downloaded from https://www.kryogenix.org/code/browser/sorttable/sorttable.js on 20211025.
 // See https://www.kryogenix.org/code/browser/sorttable/ for more info.
 //
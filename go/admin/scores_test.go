@@ -6,7 +6,12 @@ package admin
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/derat/ascenso/go/db"
 )
@@ -14,53 +19,219 @@ import (
 func TestComputeScore(t *testing.T) {
 	type cm map[string]db.ClimbState
 	type rm map[string]db.Route
+	type vm map[string]db.Verification
 
 	const (
 		r1 = "1"
 		r2 = "2"
+		b1 = "b1"
+		b2 = "b2"
+		v1 = "v1"
 	)
 
 	routes := rm{
 		r1: db.Route{Lead: 10, TR: 5, Height: 60},
 		r2: db.Route{Lead: 6, TR: 3, Height: 30},
+		b1: db.Route{Flash: 12, Top: 10, Zone: 4, Height: 15},
+		b2: db.Route{Flash: 8, Top: 6, Zone: 2, Height: 12},
+		v1: db.Route{Lead: 20, TR: 10, Height: 50, Verify: true},
 	}
 
 	for _, tc := range []struct {
 		climbs                cm
 		routes                rm
+		verifs                vm
 		points, count, height int
 	}{
-		{nil, nil, 0, 0, 0},
-		{nil, rm{}, 0, 0, 0},
-		{cm{}, nil, 0, 0, 0},
-		{cm{}, rm{}, 0, 0, 0},
-		{cm{}, routes, 0, 0, 0},
-		{cm{r1: db.Lead}, routes, 10, 1, 60},
-		{cm{r1: db.Lead, r2: db.TopRope}, routes, 13, 2, 90},
-		{cm{r1: db.Lead, "bogus": db.Lead}, routes, 10, 1, 60},
+		{nil, nil, nil, 0, 0, 0},
+		{nil, rm{}, nil, 0, 0, 0},
+		{cm{}, nil, nil, 0, 0, 0},
+		{cm{}, rm{}, nil, 0, 0, 0},
+		{cm{}, routes, nil, 0, 0, 0},
+		{cm{r1: db.Lead}, routes, nil, 10, 1, 60},
+		{cm{r1: db.Lead, r2: db.TopRope}, routes, nil, 13, 2, 90},
+		{cm{r1: db.Lead, "bogus": db.Lead}, routes, nil, 10, 1, 60},
+		{cm{b1: db.Flash}, routes, nil, 12, 1, 15},
+		{cm{b1: db.Top, b2: db.Zone}, routes, nil, 12, 1, 15},
+		{cm{r1: db.TopRope, b1: db.Zone, b2: db.Flash}, routes, nil, 17, 2, 72},
+		{cm{v1: db.Lead}, routes, nil, 0, 0, 0},
+		{cm{v1: db.Lead}, routes, vm{v1: {State: db.Lead, Status: db.Pending}}, 0, 0, 0},
+		{cm{v1: db.Lead}, routes, vm{v1: {State: db.Lead, Status: db.Approved}}, 20, 1, 50},
+		{cm{v1: db.Lead}, routes, vm{v1: {State: db.Lead, Status: db.Rejected}}, 0, 0, 0},
+		{cm{v1: db.Lead}, routes, vm{v1: {State: db.TopRope, Status: db.Approved}}, 0, 0, 0},
+		{cm{r1: db.Lead, v1: db.TopRope}, routes, vm{v1: {State: db.TopRope, Status: db.Approved}}, 20, 2, 110},
 	} {
-		points, count, height := computeScore(tc.climbs, tc.routes)
+		points, count, height := computeScore(tc.climbs, tc.routes, tc.verifs)
 		if points != tc.points || count != tc.count || height != tc.height {
-			t.Errorf("computeScore(%v, %v) = (%v, %v, %v); want (%v, %v, %v)",
-				tc.climbs, tc.routes, points, count, height, tc.points, tc.count, tc.height)
+			t.Errorf("computeScore(%v, %v, %v) = (%v, %v, %v); want (%v, %v, %v)",
+				tc.climbs, tc.routes, tc.verifs, points, count, height, tc.points, tc.count, tc.height)
 		}
 	}
 }
 
+func TestCountAttempts(t *testing.T) {
+	type cm map[string]db.ClimbState
+	type am map[string]int
+	type vm map[string]db.Verification
+
+	routes := map[string]db.Route{
+		"r1": {Lead: 10, TR: 5},
+		"b1": {Flash: 12, Top: 10, Zone: 4},
+		"b2": {Flash: 8, Top: 6, Zone: 2},
+		"v1": {Flash: 20, Top: 15, Zone: 5, Verify: true},
+	}
+
+	for _, tc := range []struct {
+		climbs   cm
+		attempts am
+		verifs   vm
+		want     int
+	}{
+		{nil, nil, nil, 0},
+		{cm{"r1": db.Lead}, am{"r1": 3}, nil, 0},
+		{cm{"b1": db.Flash}, nil, nil, 1},
+		{cm{"b1": db.Top}, nil, nil, 2},
+		{cm{"b1": db.Top, "b2": db.Zone}, am{"b1": 4, "b2": 3}, nil, 7},
+		{cm{"b1": db.Flash, "bogus": db.Top}, am{"bogus": 5}, nil, 1},
+		{cm{"b1": db.Flash}, am{"b2": 5}, nil, 1}, // attempts without climb are ignored
+		{cm{"v1": db.Top}, am{"v1": 3}, nil, 0},
+		{cm{"v1": db.Top}, am{"v1": 3}, vm{"v1": {State: db.Top, Status: db.Approved}}, 3},
+	} {
+		if got := countAttempts(tc.climbs, tc.attempts, routes, tc.verifs); got != tc.want {
+			t.Errorf("countAttempts(%v, %v, ..., %v) = %v; want %v",
+				tc.climbs, tc.attempts, tc.verifs, got, tc.want)
+		}
+	}
+}
+
+func TestSortByAttempts(t *testing.T) {
+	users := []userSummary{
+		{Name: "A", Score: 10, Attempts: 5},
+		{Name: "B", Score: 20, Attempts: 9},
+		{Name: "C", Score: 10, Attempts: 3},
+		{Name: "D", Score: 10, Attempts: 3},
+	}
+	sortUsers(users)
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	if got, want := strings.Join(names, ","), "B,C,D,A"; got != want {
+		t.Errorf("sortUsers ordered users as %v; want %v", got, want)
+	}
+
+	teams := []teamSummary{
+		{Name: "T1", Score: 10, Attempts: 5},
+		{Name: "T2", Score: 10, Attempts: 2},
+		{Name: "T3", Score: 30, Attempts: 8},
+		{Name: "T4", Score: 10, Attempts: 2},
+	}
+	sortTeams(teams)
+	names = nil
+	for _, t := range teams {
+		names = append(names, t.Name)
+	}
+	if got, want := strings.Join(names, ","), "T3,T2,T4,T1"; got != want {
+		t.Errorf("sortTeams ordered teams as %v; want %v", got, want)
+	}
+}
+
 func TestWriteScores(t *testing.T) {
 	var b bytes.Buffer
 	if err := writeScores(&b, []teamSummary{
-		{"Team A", 123, 10, 800, []userSummary{
-			{"User 1", "Team A", 100, 8, 500, ""},
-			{"User 2", "Team A", 23, 2, 300, ""},
+		{"Team A", 123, 10, 800, 0, []userSummary{
+			{Name: "User 1", Team: "Team A", Score: 100, NumClimbs: 8, Height: 500},
+			{Name: "User 2", Team: "Team A", Score: 23, NumClimbs: 2, Height: 300},
 		}},
-		{"Team B", 45, 5, 600, []userSummary{
-			{"User 3", "Team B", 25, 3, 400, ""},
-			{"User 4", "Team B", 20, 2, 200, ""},
+		{"Team B", 45, 5, 600, 0, []userSummary{
+			{Name: "User 3", Team: "Team B", Score: 25, NumClimbs: 3, Height: 400},
+			{Name: "User 4", Team: "Team B", Score: 20, NumClimbs: 2, Height: 200},
 		}},
-	}, nil); err != nil {
+	}, nil, scoresOptions{}); err != nil {
 		t.Fatal("writeScores failed: ", err)
 	}
 	// Uncomment this to view template output.
 	//fmt.Print(b.String())
+
+	b.Reset()
+	if err := writeScores(&b, nil, []userSummary{
+		{Name: "User 1", Team: "Team A", Score: 100, NumClimbs: 8, Height: 500},
+	}, scoresOptions{updated: time.Unix(0, 0), loc: &localizer{lang: "es", metres: true}}); err != nil {
+		t.Fatal("writeScores failed: ", err)
+	}
+	for _, s := range []string{"<th>Puntuación</th>", ">152 m</td>", "Actualizado 1970-01-01"} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("Localized writeScores output doesn't contain %q", s)
+		}
+	}
+}
+
+func TestMakeTeamRecords(t *testing.T) {
+	teams := []teamSummary{
+		{"Team A", 123, 10, 800, 0, []userSummary{{Name: "User 1"}, {Name: "User 2"}, {Name: "User 3"}}},
+		{"Team B", 45, 5, 600, 0, []userSummary{{Name: "User 4"}}},
+	}
+	for _, tc := range []struct {
+		teamSize int
+		want     [][]string
+	}{
+		{2, [][]string{
+			{"team", "climber_1", "climber_2", "climber_3", "score", "climbs", "height"},
+			{"Team A", "User 1", "User 2", "User 3", "123", "10", "800"},
+			{"Team B", "User 4", "", "", "45", "5", "600"},
+		}},
+		{4, [][]string{
+			{"team", "climber_1", "climber_2", "climber_3", "climber_4", "score", "climbs", "height"},
+			{"Team A", "User 1", "User 2", "User 3", "", "123", "10", "800"},
+			{"Team B", "User 4", "", "", "", "45", "5", "600"},
+		}},
+	} {
+		if got := makeTeamRecords(teams, tc.teamSize); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("makeTeamRecords(..., %d) = %q; want %q", tc.teamSize, got, tc.want)
+		}
+	}
+}
+
+func TestGetScores(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	addFakeCompetition(ff, 3, 6, 3) // first team is empty
+	sd, err := getScores(context.Background(), client, db.DefaultCompetition)
+	if err != nil {
+		t.Fatal("getScores failed: ", err)
+	}
+	if len(sd.teams) != 2 || len(sd.users) != 4 {
+		t.Fatalf("getScores returned %d team(s) and %d user(s); want 2 and 4", len(sd.teams), len(sd.users))
+	}
+	if len(sd.sorted.Areas) != 1 || len(sd.sorted.Areas[0].Routes) != 6 {
+		t.Errorf("getScores returned sorted data %+v; want 1 area with 6 routes", sd.sorted)
+	}
+	for _, ts := range sd.teams {
+		var sum int
+		for _, us := range ts.Users {
+			sum += us.Score
+		}
+		if ts.Score == 0 || ts.Score != sum {
+			t.Errorf("Team %q has score %d; members' scores sum to %d", ts.Name, ts.Score, sum)
+		}
+	}
+}
+
+// benchLatency is the simulated round-trip time for each RPC in benchmarks.
+const benchLatency = time.Millisecond
+
+func BenchmarkGetScores(b *testing.B) {
+	for _, numTeams := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("teams=%d", numTeams), func(b *testing.B) {
+			ff, client := newFakeFirestore(b, benchLatency)
+			addFakeCompetition(ff, numTeams, 50, 0)
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				if _, err := getScores(context.Background(), client, db.DefaultCompetition); err != nil {
+					b.Fatal("getScores failed: ", err)
+				}
+			}
+			b.ReportMetric(float64(numTeams*b.N)/time.Since(start).Seconds(), "teams/s")
+		})
+	}
 }
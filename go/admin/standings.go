@@ -0,0 +1,185 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+const (
+	// maxStandingsAge is the maximum age of precomputed standings before they're recomputed.
+	// This bounds the number of full scans of the teams collection regardless of how many
+	// scoreboard requests are received.
+	maxStandingsAge = 30 * time.Second
+
+	// standingsLeaseTime is the amount of time that a request has to recompute stale
+	// standings before another request may try. Other requests use the stale standings
+	// in the meantime.
+	standingsLeaseTime = time.Minute
+)
+
+// handleUpdateStandings handles an "updateStandings" POST request.
+// It recomputes competition comp's precomputed standings.
+func handleUpdateStandings(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	teams, _, _, err := getStandings(ctx, client, comp, 0)
+	if err != nil {
+		http.Error(w, loc.T("Failed updating standings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Updated standings for %d team(s)", len(teams)))
+}
+
+// getStandings returns competition comp's standings from the doc at db.StandingsDocPath
+// along with the time at which they were computed. If the doc is missing or was computed
+// more than maxAge ago, the standings are recomputed using getScores and saved. While
+// one request is recomputing stale standings, other requests return the stale ones.
+// If maxAge is 0, the standings are always recomputed.
+func getStandings(ctx context.Context, client *firestore.Client, comp string, maxAge time.Duration) (
+	[]teamSummary, []userSummary, time.Time, error) {
+	ref := client.Doc(db.StandingsDocPath(comp))
+	coll := client.Collection(db.StandingsTeamCollectionPath(comp))
+	for attempt := 0; ; attempt++ {
+		st, recompute, err := claimStandings(ctx, client, ref, maxAge)
+		if err != nil {
+			return nil, nil, time.Time{}, err
+		}
+		if recompute {
+			return updateStandings(ctx, client, comp)
+		}
+		if st.Time.IsZero() {
+			// Another request is computing the initial standings, so there's nothing to return
+			// yet. Compute them without saving them.
+			now := time.Now()
+			sd, err := getScores(ctx, client, comp)
+			if err != nil {
+				return nil, nil, time.Time{}, err
+			}
+			return sd.teams, sd.users, now, nil
+		}
+
+		ats, err := readTeamChunks(ctx, client, coll, st.ChunkPrefix, st.NumChunks)
+		if err == errChunkMissing && attempt == 0 {
+			// The chunks were deleted after the standings were updated by another request.
+			continue
+		} else if err != nil {
+			return nil, nil, time.Time{}, fmt.Errorf("failed getting standings: %v", err)
+		}
+		var sorted db.SortedData
+		if err := db.GetDoc(ctx, client.Doc(db.SortedDataDocPath(comp)), &sorted); err != nil {
+			return nil, nil, time.Time{}, fmt.Errorf("failed getting sorted data: %v", err)
+		}
+		teams, users := getArchivedScores(ats, sorted.Areas)
+		return teams, users, st.Time, nil
+	}
+}
+
+// claimStandings reads the standings doc at ref. If the standings are stale per maxAge
+// and aren't already being recomputed by another request, the standings are leased for
+// standingsLeaseTime and true is returned to indicate that the caller should recompute
+// them. If the caller fails to do so, the lease expires. If maxAge is 0, true is always
+// returned.
+func claimStandings(ctx context.Context, client *firestore.Client, ref *firestore.DocumentRef,
+	maxAge time.Duration) (db.Standings, bool, error) {
+	var st db.Standings
+	if maxAge == 0 {
+		return st, true, nil
+	}
+	// Avoid the cost of a transaction in the common case where the standings are fresh.
+	if _, err := getDocIfExists(ctx, ref, &st); err != nil {
+		return st, false, fmt.Errorf("failed getting standings: %v", err)
+	} else if !standingsStale(st.Time, time.Now(), maxAge) {
+		return st, false, nil
+	}
+
+	var claimed bool
+	if err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		st, claimed = db.Standings{}, false
+		if snap, err := tx.Get(ref); status.Code(err) == codes.NotFound {
+			// Claim the missing standings below.
+		} else if err != nil {
+			return err
+		} else if err := snap.DataTo(&st); err != nil {
+			return err
+		}
+		now := time.Now()
+		if !standingsStale(st.Time, now, maxAge) || now.Before(st.LeaseExpiry) {
+			return nil
+		}
+		claimed = true
+		return tx.Set(ref, map[string]interface{}{"leaseExpiry": now.Add(standingsLeaseTime)}, firestore.MergeAll)
+	}); err != nil {
+		return st, false, fmt.Errorf("failed claiming standings: %v", err)
+	}
+	return st, claimed, nil
+}
+
+// updateStandings recomputes competition comp's standings using getScores and saves them
+// to db.TeamChunk docs referenced by the doc at db.StandingsDocPath, releasing the lease
+// taken by claimStandings. The previous standings' chunks are deleted.
+func updateStandings(ctx context.Context, client *firestore.Client, comp string) (
+	[]teamSummary, []userSummary, time.Time, error) {
+	now := time.Now()
+	sd, err := getScores(ctx, client, comp)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	teams, users := sd.teams, sd.users
+
+	ref := client.Doc(db.StandingsDocPath(comp))
+	coll := client.Collection(db.StandingsTeamCollectionPath(comp))
+	st := db.Standings{Time: now, ChunkPrefix: fmt.Sprintf("%d-", now.UnixNano())}
+	if st.NumChunks, err = writeTeamChunks(ctx, client, coll, st.ChunkPrefix, newArchivedTeams(teams, true)); err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("failed writing standings: %v", err)
+	}
+	var old db.Standings
+	if err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		old = db.Standings{}
+		if snap, err := tx.Get(ref); status.Code(err) == codes.NotFound {
+			// Nothing to replace.
+		} else if err != nil {
+			return err
+		} else if err := snap.DataTo(&old); err != nil {
+			return err
+		}
+		return tx.Set(ref, st)
+	}); err != nil {
+		if err := deleteTeamChunks(ctx, client, coll, st.ChunkPrefix, st.NumChunks); err != nil {
+			log.Printf("Failed deleting unused chunks from %v: %v", coll.Path, err)
+		}
+		return nil, nil, time.Time{}, fmt.Errorf("failed writing standings: %v", err)
+	}
+
+	// Requests that read the old doc before it was replaced will retry if the chunks are gone.
+	if err := deleteTeamChunks(ctx, client, coll, old.ChunkPrefix, old.NumChunks); err != nil {
+		log.Printf("Failed deleting old chunks from %v: %v", coll.Path, err)
+	}
+	return teams, users, now, nil
+}
+
+// standingsStale returns true if standings computed at t should be recomputed at now.
+func standingsStale(t, now time.Time, maxAge time.Duration) bool {
+	return t.IsZero() || now.Sub(t) > maxAge || t.After(now)
+}
+
+// invalidateStandings makes the next scoreboard request recompute competition comp's
+// standings. It should be called after changing climbs or verifications.
+func invalidateStandings(ctx context.Context, client *firestore.Client, comp string) error {
+	if _, err := client.Doc(db.StandingsDocPath(comp)).Update(ctx,
+		[]firestore.Update{{Path: "time", Value: time.Time{}}}); err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("failed invalidating standings: %v", err)
+	}
+	return nil
+}
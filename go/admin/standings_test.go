@@ -0,0 +1,131 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestStandingsStale(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	for _, tc := range []struct {
+		t      time.Time
+		maxAge time.Duration
+		want   bool
+	}{
+		{time.Time{}, time.Minute, true},
+		{now, time.Minute, false},
+		{now.Add(-30 * time.Second), time.Minute, false},
+		{now.Add(-2 * time.Minute), time.Minute, true},
+		{now.Add(-time.Second), 0, true},
+		{now.Add(time.Hour), time.Minute, true}, // clock skew
+	} {
+		if got := standingsStale(tc.t, now, tc.maxAge); got != tc.want {
+			t.Errorf("standingsStale(%v, %v, %v) = %v; want %v", tc.t, now, tc.maxAge, got, tc.want)
+		}
+	}
+}
+
+func TestGetStandings(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	addFakeCompetition(ff, 1000, 100, 0)
+	ctx := context.Background()
+	comp := db.DefaultCompetition
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// The first request should compute and save the standings.
+	teams, users, updated, err := getStandings(ctx, client, comp, time.Hour)
+	if err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+	if n := ff.queryCount("teams"); n != 1 {
+		t.Errorf("Teams were queried %d time(s) initially; want 1", n)
+	}
+	numChunks := ff.count("standings/current/teams")
+	if numChunks < 2 {
+		t.Errorf("Standings have %d chunk(s); want at least 2", numChunks)
+	}
+
+	// The next request should use the saved standings.
+	gotTeams, gotUsers, gotUpdated, err := getStandings(ctx, client, comp, time.Hour)
+	if err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+	if n := ff.queryCount("teams"); n != 1 {
+		t.Errorf("Teams were queried %d time(s) after fresh request; want 1", n)
+	}
+	if !gotUpdated.Equal(updated) || !reflect.DeepEqual(gotTeams, teams) || !reflect.DeepEqual(gotUsers, users) {
+		t.Error("getStandings returned different saved standings")
+	}
+
+	// Updating the standings should replace the old chunks.
+	if _, _, _, err := getStandings(ctx, client, comp, 0); err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+	if n := ff.queryCount("teams"); n != 2 {
+		t.Errorf("Teams were queried %d time(s) after update; want 2", n)
+	}
+	if n := ff.count("standings/current/teams"); n != numChunks {
+		t.Errorf("Standings have %d chunk(s) after update; want %d", n, numChunks)
+	}
+}
+
+func TestGetStandings_Concurrent(t *testing.T) {
+	ff, client := newFakeFirestore(t, 5*time.Millisecond)
+	addFakeCompetition(ff, 100, 10, 0)
+	ctx := context.Background()
+	comp := db.DefaultCompetition
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	_, _, initial, err := getStandings(ctx, client, comp, 0)
+	if err != nil {
+		t.Fatal("getStandings failed: ", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	// Only one of many concurrent requests for stale standings should recompute them.
+	// The others should return the stale standings.
+	const numReqs = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var numStale int
+	for i := 0; i < numReqs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, updated, err := getStandings(ctx, client, comp, time.Millisecond)
+			if err != nil {
+				t.Error("getStandings failed: ", err)
+				return
+			}
+			if updated.Equal(initial) {
+				mu.Lock()
+				numStale++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := ff.queryCount("teams"); n != 2 {
+		t.Errorf("Teams were queried %d time(s); want 2", n)
+	}
+	if numStale != numReqs-1 {
+		t.Errorf("%d of %d request(s) returned stale standings; want %d", numStale, numReqs, numReqs-1)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handleRouteStats handles a "routeStats" POST request.
+// It writes an HTML document with per-route statistics computed from all users' climbs.
+func handleRouteStats(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	users, areas, verifs, err := loadStatsData(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := writeRouteStats(w, computeRouteStats(users, areas, verifs), loc); err != nil {
+		http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// handleRouteStatsCSV handles a "routeStatsCsv" POST request.
+// It is similar to handleRouteStats but writes CSV data.
+func handleRouteStatsCSV(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	users, areas, verifs, err := loadStatsData(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats := computeRouteStats(users, areas, verifs)
+	recs := [][]string{{"area", "id", "name", "grade", "lead", "tr", "flash", "top", "zone",
+		"sends", "sent_pct", "points"}}
+	for _, st := range stats {
+		recs = append(recs, []string{
+			st.Area, st.ID, st.Name, st.Grade,
+			strconv.Itoa(st.Lead), strconv.Itoa(st.TR), strconv.Itoa(st.Flash),
+			strconv.Itoa(st.Top), strconv.Itoa(st.Zone), strconv.Itoa(st.Sends),
+			strconv.FormatFloat(st.SentPct, 'f', 1, 64), strconv.Itoa(st.Points),
+		})
+	}
+	setCSVHeaders(w.Header(), "routes-stats.csv")
+	if err := csv.NewWriter(w).WriteAll(recs); err != nil {
+		http.Error(w, loc.T("Failed writing stats: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// routeStats describes how a route performed.
+// Only climbs that count toward users' scores are included, so climbs of routes
+// requiring verification are omitted until they're approved.
+type routeStats struct {
+	ID    string
+	Name  string
+	Area  string // area name
+	Grade string
+
+	Lead, TR, Flash, Top, Zone int // number of climbs in each state
+
+	Sends   int     // number of users who led, top-roped, flashed, or topped the route
+	SentPct float64 // percentage of participants who sent the route
+	Points  int     // total points awarded for the route
+}
+
+// loadStatsData loads competition comp's current users, areas, and verifications
+// (keyed by user ID) for computing statistics.
+func loadStatsData(ctx context.Context, client *firestore.Client, comp string) (
+	[]userSummary, []db.Area, map[string]db.UserVerifications, error) {
+	sd, err := getScores(ctx, client, comp)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed loading scores: %v", err)
+	}
+	return sd.users, sd.sorted.Areas, sd.verifs, nil
+}
+
+// countedClimb returns true if a climb of rt in state counts toward the user's score,
+// i.e. the route doesn't require verification or verif approves the climb.
+func countedClimb(rt db.Route, state db.ClimbState, verif db.Verification) bool {
+	return state != db.NotClimbed && (!rt.Verify || verif.GetStatus(state) == db.Approved)
+}
+
+// computeRouteStats returns statistics for each route in areas (in order) based on
+// the climbs of users. verifs is keyed by user ID.
+func computeRouteStats(users []userSummary, areas []db.Area,
+	verifs map[string]db.UserVerifications) []routeStats {
+	var stats []routeStats
+	for _, a := range areas {
+		for _, rt := range a.Routes {
+			st := routeStats{ID: rt.ID, Name: rt.Name, Area: a.Name, Grade: rt.Grade}
+			for _, u := range users {
+				state, ok := u.Climbs[rt.ID]
+				if !ok {
+					continue
+				}
+				verif := verifs[u.UID].Climbs[rt.ID]
+				if !countedClimb(rt, state, verif) {
+					continue
+				}
+				points, ascent := climbPoints(rt, state, verif)
+				switch state {
+				case db.Lead:
+					st.Lead++
+				case db.TopRope:
+					st.TR++
+				case db.Flash:
+					st.Flash++
+				case db.Top:
+					st.Top++
+				case db.Zone:
+					st.Zone++
+				}
+				if ascent {
+					st.Sends++
+				}
+				st.Points += points
+			}
+			if len(users) > 0 {
+				st.SentPct = 100 * float64(st.Sends) / float64(len(users))
+			}
+			stats = append(stats, st)
+		}
+	}
+	return stats
+}
+
+// writeRouteStats writes an HTML document describing stats to w, localized using loc.
+func writeRouteStats(w io.Writer, stats []routeStats, loc *localizer) error {
+	tmpl, err := parseTableTemplate(routeStatsTemplate, loc.funcs())
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, struct {
+		SorttableJS template.JS
+		Routes      []routeStats
+	}{
+		SorttableJS: template.JS(sorttableJS),
+		Routes:      stats,
+	})
+}
+
+// maxAreaTopRoutes is the maximum number of most-climbed routes listed for each area.
+const maxAreaTopRoutes = 3
+
+// handleAreaStats handles an "areaStats" POST request.
+// It writes an HTML document with per-area statistics, shading cells to show which
+// areas saw the most activity.
+func handleAreaStats(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	users, areas, verifs, err := loadStatsData(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := writeAreaStats(w, computeAreaStats(users, areas, verifs), loc); err != nil {
+		http.Error(w, loc.T("Failed writing template: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// areaStats describes how an area performed.
+// As with routeStats, only climbs that count toward users' scores are included.
+type areaStats struct {
+	ID        string
+	Name      string
+	NumRoutes int
+	Ascents   int          // number of sends of the area's routes
+	Climbers  int          // number of users with at least one climb (including zones) in the area
+	Points    int          // total points awarded for the area's routes
+	AvgPoints float64      // average points per climber in the area
+	TopRoutes []routeStats // most-sent routes in descending order
+}
+
+// computeAreaStats returns statistics for each area in areas (in order) based on
+// the climbs of users. verifs is keyed by user ID.
+func computeAreaStats(users []userSummary, areas []db.Area,
+	verifs map[string]db.UserVerifications) []areaStats {
+	rstats := computeRouteStats(users, areas, verifs)
+	var stats []areaStats
+	for _, a := range areas {
+		st := areaStats{ID: a.ID, Name: a.Name, NumRoutes: len(a.Routes)}
+		routes := append([]routeStats(nil), rstats[:len(a.Routes)]...)
+		rstats = rstats[len(a.Routes):]
+		for _, rs := range routes {
+			st.Ascents += rs.Sends
+			st.Points += rs.Points
+		}
+		for _, u := range users {
+			for _, rt := range a.Routes {
+				if countedClimb(rt, u.Climbs[rt.ID], verifs[u.UID].Climbs[rt.ID]) {
+					st.Climbers++
+					break
+				}
+			}
+		}
+		if st.Climbers > 0 {
+			st.AvgPoints = float64(st.Points) / float64(st.Climbers)
+		}
+
+		sort.SliceStable(routes, func(i, j int) bool { return routes[i].Sends > routes[j].Sends })
+		for _, rs := range routes {
+			if rs.Sends == 0 || len(st.TopRoutes) == maxAreaTopRoutes {
+				break
+			}
+			st.TopRoutes = append(st.TopRoutes, rs)
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// heatStyle returns a CSS declaration shading a table cell in proportion to val/max.
+func heatStyle(val, max float64) template.CSS {
+	if max <= 0 || val <= 0 {
+		return ""
+	}
+	return template.CSS(fmt.Sprintf("background-color: rgba(230, 81, 0, %.2f)", 0.6*val/max))
+}
+
+// writeAreaStats writes an HTML document describing stats to w, localized using loc.
+func writeAreaStats(w io.Writer, stats []areaStats, loc *localizer) error {
+	tmpl, err := parseTableTemplate(areaStatsTemplate, loc.funcs())
+	if err != nil {
+		return err
+	}
+
+	type areaRow struct {
+		areaStats
+		AscentsStyle, ClimbersStyle, AvgPointsStyle template.CSS
+	}
+	var maxAscents, maxClimbers, maxAvgPoints float64
+	for _, st := range stats {
+		maxAscents = math.Max(maxAscents, float64(st.Ascents))
+		maxClimbers = math.Max(maxClimbers, float64(st.Climbers))
+		maxAvgPoints = math.Max(maxAvgPoints, st.AvgPoints)
+	}
+	rows := make([]areaRow, len(stats))
+	for i, st := range stats {
+		rows[i] = areaRow{
+			areaStats:      st,
+			AscentsStyle:   heatStyle(float64(st.Ascents), maxAscents),
+			ClimbersStyle:  heatStyle(float64(st.Climbers), maxClimbers),
+			AvgPointsStyle: heatStyle(st.AvgPoints, maxAvgPoints),
+		}
+	}
+
+	return tmpl.Execute(w, struct {
+		SorttableJS template.JS
+		Areas       []areaRow
+	}{
+		SorttableJS: template.JS(sorttableJS),
+		Areas:       rows,
+	})
+}
+
+const routeStatsTemplate = `
+<!DOCTYPE html>
+<html>
+  <head>
+    <title>{{T "Route statistics"}}</title>
+{{- template "tableHead" .}}
+  </head>
+  <body>
+    <table class="sortable">
+      <thead>
+        <tr>
+          <th>{{T "Area"}}</th>
+          <th>{{T "Route"}}</th>
+          <th>{{T "Grade"}}</th>
+          <th>{{T "Lead"}}</th>
+          <th>{{T "TR"}}</th>
+          <th>{{T "Flash"}}</th>
+          <th>{{T "Top"}}</th>
+          <th>{{T "Zone"}}</th>
+          <th>{{T "Sends"}}</th>
+          <th>{{T "Sent"}}</th>
+          <th>{{T "Points"}}</th>
+        </tr>
+      </thead>
+      <tbody>
+{{- range .Routes}}
+        <tr>
+          <td>{{.Area}}</td>
+          <td title="{{.ID}}">{{.Name}}</td>
+          <td>{{.Grade}}</td>
+          <td class="num">{{.Lead}}</td>
+          <td class="num">{{.TR}}</td>
+          <td class="num">{{.Flash}}</td>
+          <td class="num">{{.Top}}</td>
+          <td class="num">{{.Zone}}</td>
+          <td class="num">{{.Sends}}</td>
+          <td class="num" sorttable_customkey="{{.SentPct}}">{{printf "%.1f" .SentPct}}%</td>
+          <td class="num">{{.Points}}</td>
+        </tr>
+{{- end}}
+      </tbody>
+    </table>
+  </body>
+</html>
+`
+
+const areaStatsTemplate = `
+<!DOCTYPE html>
+<html>
+  <head>
+    <title>{{T "Area statistics"}}</title>
+{{- template "tableHead" .}}
+  </head>
+  <body>
+    <table class="sortable">
+      <thead>
+        <tr>
+          <th>{{T "Area"}}</th>
+          <th>{{T "Routes"}}</th>
+          <th>{{T "Ascents"}}</th>
+          <th>{{T "Climbers"}}</th>
+          <th>{{T "Points"}}</th>
+          <th>{{T "Avg. points"}}</th>
+          <th>{{T "Most climbed"}}</th>
+        </tr>
+      </thead>
+      <tbody>
+{{- range .Areas}}
+        <tr>
+          <td title="{{.ID}}">{{.Name}}</td>
+          <td class="num">{{.NumRoutes}}</td>
+          <td class="num" style="{{.AscentsStyle}}">{{.Ascents}}</td>
+          <td class="num" style="{{.ClimbersStyle}}">{{.Climbers}}</td>
+          <td class="num">{{.Points}}</td>
+          <td class="num" style="{{.AvgPointsStyle}}" sorttable_customkey="{{.AvgPoints}}">{{printf "%.1f" .AvgPoints}}</td>
+          <td>{{range $i, $r := .TopRoutes}}{{if $i}}, {{end}}{{$r.Name}} ({{$r.Sends}}){{end}}</td>
+        </tr>
+{{- end}}
+      </tbody>
+    </table>
+  </body>
+</html>
+`
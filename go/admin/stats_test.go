@@ -0,0 +1,84 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// statsAreas and statsUsers are used by tests of statistics functions.
+var statsAreas = []db.Area{
+	{ID: "a1", Name: "Area 1", Routes: []db.Route{
+		{ID: "r1", Name: "Route 1", Grade: "5.10a", Lead: 10, TR: 5, Height: 60},
+		{ID: "r2", Name: "Route 2", Grade: "5.12a", Lead: 30, TR: 15, Height: 80, Verify: true},
+	}},
+	{ID: "a2", Name: "Area 2", Routes: []db.Route{
+		{ID: "b1", Name: "Boulder 1", Grade: "V2", Flash: 12, Top: 10, Zone: 4, Height: 15},
+	}},
+}
+var statsUsers = []userSummary{
+	{UID: "u1", Name: "User 1", Climbs: map[string]db.ClimbState{"r1": db.Lead, "r2": db.Lead, "b1": db.Flash}},
+	{UID: "u2", Name: "User 2", Climbs: map[string]db.ClimbState{"r1": db.TopRope, "r2": db.TopRope, "b1": db.Zone}},
+	{UID: "u3", Name: "User 3", Climbs: map[string]db.ClimbState{"r1": db.Lead}},
+	{UID: "u4", Name: "User 4"},
+}
+var statsVerifs = map[string]db.UserVerifications{
+	"u1": {Climbs: map[string]db.Verification{"r2": {State: db.Lead, Status: db.Approved}}},
+	"u2": {Climbs: map[string]db.Verification{"r2": {State: db.TopRope, Status: db.Rejected}}},
+}
+
+func TestComputeRouteStats(t *testing.T) {
+	got := computeRouteStats(statsUsers, statsAreas, statsVerifs)
+	want := []routeStats{
+		{ID: "r1", Name: "Route 1", Area: "Area 1", Grade: "5.10a", Lead: 2, TR: 1, Sends: 3, SentPct: 75, Points: 25},
+		{ID: "r2", Name: "Route 2", Area: "Area 1", Grade: "5.12a", Lead: 1, Sends: 1, SentPct: 25, Points: 30},
+		{ID: "b1", Name: "Boulder 1", Area: "Area 2", Grade: "V2", Flash: 1, Zone: 1, Sends: 1, SentPct: 25, Points: 16},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeRouteStats returned %+v; want %+v", got, want)
+	}
+}
+
+func TestWriteRouteStats(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeRouteStats(&b, computeRouteStats(statsUsers, statsAreas, statsVerifs), defaultLocalizer); err != nil {
+		t.Fatal("writeRouteStats failed: ", err)
+	}
+	if !bytes.Contains(b.Bytes(), []byte("75.0%")) {
+		t.Error("writeRouteStats output doesn't contain sent percentage")
+	}
+}
+
+func TestComputeAreaStats(t *testing.T) {
+	got := computeAreaStats(statsUsers, statsAreas, statsVerifs)
+	rs := computeRouteStats(statsUsers, statsAreas, statsVerifs)
+	want := []areaStats{
+		{ID: "a1", Name: "Area 1", NumRoutes: 2, Ascents: 4, Climbers: 3, Points: 55,
+			AvgPoints: 55.0 / 3, TopRoutes: []routeStats{rs[0], rs[1]}},
+		{ID: "a2", Name: "Area 2", NumRoutes: 1, Ascents: 1, Climbers: 2, Points: 16,
+			AvgPoints: 8, TopRoutes: []routeStats{rs[2]}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeAreaStats returned %+v; want %+v", got, want)
+	}
+}
+
+func TestWriteAreaStats(t *testing.T) {
+	var b bytes.Buffer
+	loc := &localizer{lang: "es"}
+	if err := writeAreaStats(&b, computeAreaStats(statsUsers, statsAreas, statsVerifs), loc); err != nil {
+		t.Fatal("writeAreaStats failed: ", err)
+	}
+	for _, s := range []string{"Route 1 (3), Route 2 (1)", "18.3", "rgba(230, 81, 0, 0.60)",
+		"<title>Estadísticas por área</title>", "<th>Ascensos</th>"} {
+		if !bytes.Contains(b.Bytes(), []byte(s)) {
+			t.Errorf("writeAreaStats output doesn't contain %q", s)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+const (
+	// maxChunkBytes is the maximum JSON-encoded size of the teams in a db.TeamChunk doc.
+	// Firestore's encoding can be up to about twice as large (integers take eight bytes),
+	// so this keeps docs well under the 1 MiB document size limit.
+	maxChunkBytes = 256 << 10
+
+	// maxChunksPerBatch is the maximum number of db.TeamChunk docs written in a single
+	// batch. This keeps commit requests under Firestore's 10 MiB request size limit.
+	maxChunksPerBatch = 8
+)
+
+// errChunkMissing is returned by readTeamChunks if a chunk doesn't exist.
+var errChunkMissing = errors.New("team chunk missing")
+
+// chunkTeams splits ats into groups that are small enough to store in db.TeamChunk docs.
+// The order of ats is preserved.
+func chunkTeams(ats []db.ArchivedTeam) ([][]db.ArchivedTeam, error) {
+	var chunks [][]db.ArchivedTeam
+	var cur []db.ArchivedTeam
+	var size int
+	for _, at := range ats {
+		b, err := json.Marshal(at)
+		if err != nil {
+			return nil, err
+		} else if len(b) > maxChunkBytes {
+			return nil, fmt.Errorf("team %q is too large (%d bytes)", at.Name, len(b))
+		}
+		if len(cur) > 0 && size+len(b) > maxChunkBytes {
+			chunks = append(chunks, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, at)
+		size += len(b)
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks, nil
+}
+
+// writeTeamChunks splits ats into chunks and writes them to db.TeamChunk docs in coll
+// with IDs starting with prefix. The number of written chunks is returned.
+func writeTeamChunks(ctx context.Context, client *firestore.Client, coll *firestore.CollectionRef,
+	prefix string, ats []db.ArchivedTeam) (int, error) {
+	chunks, err := chunkTeams(ats)
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(chunks); start += maxChunksPerBatch {
+		end := start + maxChunksPerBatch
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+		batch := client.Batch()
+		for i := start; i < end; i++ {
+			batch.Set(coll.Doc(db.TeamChunkID(prefix, i)), db.TeamChunk{Teams: chunks[i]})
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("failed writing chunks: %v", err)
+		}
+	}
+	return len(chunks), nil
+}
+
+// readTeamChunks reads the n db.TeamChunk docs in coll with IDs starting with prefix
+// and returns their teams in order. errChunkMissing is returned if a doc doesn't exist.
+func readTeamChunks(ctx context.Context, client *firestore.Client, coll *firestore.CollectionRef,
+	prefix string, n int) ([]db.ArchivedTeam, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	refs := make([]*firestore.DocumentRef, n)
+	for i := range refs {
+		refs[i] = coll.Doc(db.TeamChunkID(prefix, i))
+	}
+	snaps, err := client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting chunks: %v", err)
+	}
+	var ats []db.ArchivedTeam
+	for _, snap := range snaps {
+		if !snap.Exists() {
+			return nil, errChunkMissing
+		}
+		var chunk db.TeamChunk
+		if err := snap.DataTo(&chunk); err != nil {
+			return nil, fmt.Errorf("failed decoding %v: %v", snap.Ref.Path, err)
+		}
+		ats = append(ats, chunk.Teams...)
+	}
+	return ats, nil
+}
+
+// deleteTeamChunks deletes the n db.TeamChunk docs in coll with IDs starting with prefix.
+func deleteTeamChunks(ctx context.Context, client *firestore.Client, coll *firestore.CollectionRef,
+	prefix string, n int) error {
+	for start := 0; start < n; start += maxBatchWrites {
+		end := start + maxBatchWrites
+		if end > n {
+			end = n
+		}
+		batch := client.Batch()
+		for i := start; i < end; i++ {
+			batch.Delete(coll.Doc(db.TeamChunkID(prefix, i)))
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return fmt.Errorf("failed deleting chunks: %v", err)
+		}
+	}
+	return nil
+}
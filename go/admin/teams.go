@@ -0,0 +1,448 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+const (
+	maxNameLength    = 50 // matches nameValid() in firestore.rules
+	inviteCodeLength = 6  // matches inviteCodeValid() in firestore.rules
+
+	maxInviteAttempts = 5 // max attempts to find an unused invite code
+)
+
+// handleListTeams handles a "listTeams" POST request.
+// It writes each team's ID, invite code, name, and members.
+func handleListTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var lines []string
+	it := client.Collection(db.TeamCollectionPath(comp)).Documents(ctx)
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			http.Error(w, loc.T("Failed getting team: %v", err), http.StatusInternalServerError)
+			return
+		}
+		var team db.Team
+		if err := snap.DataTo(&team); err != nil {
+			http.Error(w, loc.T("Failed decoding %v: %v", snap.Ref.Path, err), http.StatusInternalServerError)
+			return
+		}
+		members := make([]string, 0, len(team.Users))
+		for uid, u := range team.Users {
+			members = append(members, fmt.Sprintf("%v (%q)", uid, u.Name))
+		}
+		sort.Strings(members)
+		lines = append(lines, fmt.Sprintf("%q %v %v: %v", team.Name, snap.Ref.ID, team.Invite,
+			strings.Join(members, ", ")))
+	}
+	sort.Strings(lines)
+
+	fmt.Fprintln(w, loc.T("%d team(s)", len(lines)))
+	for _, ln := range lines {
+		fmt.Fprintln(w, ln)
+	}
+}
+
+// handleRenameTeam handles a "renameTeam" POST request.
+// It sets the name of the team identified by the "team" parameter to the "name" parameter.
+func handleRenameTeam(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	name, err := getNameParam(r)
+	if err != nil {
+		http.Error(w, loc.T("Bad name: %v", err), http.StatusBadRequest)
+		return
+	}
+	ref, team, err := findTeam(ctx, client, comp, r.FormValue("team"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+
+	log.Printf("Renaming %v from %q to %q", ref.Path, team.Name, name)
+	if _, err := ref.Update(ctx, []firestore.Update{{Path: "name", Value: name}}); err != nil {
+		http.Error(w, loc.T("Failed updating team: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Renamed team %q to %q", team.Name, name))
+}
+
+// handleMergeTeams handles a "mergeTeams" POST request.
+// It moves all members of the team identified by the "otherTeam" parameter to the
+// team identified by the "team" parameter and deletes the other team and its invite.
+func handleMergeTeams(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	dstRef, dst, err := findTeam(ctx, client, comp, r.FormValue("team"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+	srcRef, src, err := findTeam(ctx, client, comp, r.FormValue("otherTeam"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+	if srcRef.ID == dstRef.ID {
+		http.Error(w, loc.T("Can't merge team with itself"), http.StatusBadRequest)
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Members who left a team still count toward its size since their climbs are kept.
+	if n := len(dst.Users) + len(src.Users); n > cfg.GetTeamSize() {
+		http.Error(w, loc.T("Merged team would have %d members", n), http.StatusBadRequest)
+		return
+	}
+	for uid := range src.Users {
+		if _, ok := dst.Users[uid]; ok {
+			http.Error(w, loc.T("User %q is listed on both teams", uid), http.StatusBadRequest)
+			return
+		}
+	}
+
+	batch := client.Batch()
+	var updates []firestore.Update
+	for uid, u := range src.Users {
+		// Members who left the source team keep their climbs there (and thus in the
+		// merged team), but they may be on another team now, so leave their user docs alone.
+		updates = append(updates, firestore.Update{FieldPath: firestore.FieldPath{"users", uid}, Value: nonNilClimbs(u)})
+		if !u.Left {
+			batch.Update(client.Collection(db.UserCollectionPath(comp)).Doc(uid),
+				[]firestore.Update{{Path: "team", Value: dstRef.ID}})
+		}
+	}
+	if len(updates) > 0 {
+		batch.Update(dstRef, updates)
+	}
+	batch.Delete(srcRef)
+	if src.Invite != "" {
+		batch.Delete(client.Collection(db.InviteCollectionPath(comp)).Doc(src.Invite))
+	}
+
+	log.Printf("Merging %v (%+v) into %v (%+v)", srcRef.Path, src, dstRef.Path, dst)
+	if _, err := batch.Commit(ctx); err != nil {
+		http.Error(w, loc.T("Failed merging teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Merged team %q into %q", src.Name, dst.Name))
+}
+
+// handleSplitTeam handles a "splitTeam" POST request.
+// It moves the user identified by the "user" parameter from the team identified by
+// the "team" parameter to a new team named by the "name" parameter (or the user's
+// name, if empty) with a new invite code.
+func handleSplitTeam(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	oldRef, old, err := findTeam(ctx, client, comp, r.FormValue("team"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+	uid := r.FormValue("user")
+	u, ok := old.Users[uid]
+	if !ok {
+		http.Error(w, loc.T("User %q not on team %q", uid, old.Name), http.StatusBadRequest)
+		return
+	}
+	if u.Left {
+		http.Error(w, loc.T("User %q already left team %q", uid, old.Name), http.StatusBadRequest)
+		return
+	}
+	if activeMembers(old) < 2 {
+		http.Error(w, loc.T("User %q is the only member of team %q", uid, old.Name), http.StatusBadRequest)
+		return
+	}
+	name := u.Name
+	if r.FormValue("name") != "" {
+		if name, err = getNameParam(r); err != nil {
+			http.Error(w, loc.T("Bad name: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	newRef := client.Collection(db.TeamCollectionPath(comp)).NewDoc()
+	code, err := commitWithInvite(ctx, client, comp, newRef.ID, func(code string, batch *firestore.WriteBatch) {
+		batch.Create(newRef, db.Team{
+			Name:   name,
+			Invite: code,
+			Users:  map[string]db.TeamUser{uid: nonNilClimbs(u)},
+		})
+		batch.Update(oldRef, []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: firestore.Delete}})
+		batch.Update(client.Collection(db.UserCollectionPath(comp)).Doc(uid),
+			[]firestore.Update{{Path: "team", Value: newRef.ID}})
+		log.Printf("Moving user %v from %v to new team %v with invite %v", uid, oldRef.Path, newRef.Path, code)
+	})
+	if err != nil {
+		http.Error(w, loc.T("Failed splitting team: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Moved %q from team %q to new team %q with invite code %v", u.Name, old.Name, name, code))
+}
+
+// handleMoveUser handles a "moveUser" POST request.
+// It moves the user identified by the "user" parameter from their current team (if any)
+// to the team identified by the "team" parameter. The user's old team and its invite
+// are deleted if the user was its only member.
+func handleMoveUser(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	uid := r.FormValue("user")
+	if uid == "" {
+		http.Error(w, loc.T("User not supplied"), http.StatusBadRequest)
+		return
+	}
+	userRef := client.Collection(db.UserCollectionPath(comp)).Doc(uid)
+	var user db.User
+	if ok, err := getDocIfExists(ctx, userRef, &user); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !ok {
+		http.Error(w, loc.T("User %q not found", uid), http.StatusBadRequest)
+		return
+	}
+
+	dstRef, dst, err := findTeam(ctx, client, comp, r.FormValue("team"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+	if user.Team == dstRef.ID {
+		http.Error(w, loc.T("User %q is already on team %q", uid, dst.Name), http.StatusBadRequest)
+		return
+	}
+	cfg, err := getConfig(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(dst.Users) >= cfg.GetTeamSize() {
+		http.Error(w, loc.T("Team %q is full", dst.Name), http.StatusBadRequest)
+		return
+	}
+	// Don't overwrite climbs that the user reported before leaving the destination team.
+	if _, ok := dst.Users[uid]; ok {
+		http.Error(w, loc.T("User %q previously left team %q", uid, dst.Name), http.StatusBadRequest)
+		return
+	}
+
+	batch := client.Batch()
+	var entry db.TeamUser
+	if user.Team != "" {
+		srcRef, src, err := findTeam(ctx, client, comp, user.Team)
+		if err != nil {
+			writeTeamError(w, loc, err)
+			return
+		}
+		var ok bool
+		if entry, ok = src.Users[uid]; !ok {
+			http.Error(w, loc.T("User %q not listed in team %q", uid, src.Name), http.StatusInternalServerError)
+			return
+		}
+		if len(src.Users) == 1 {
+			log.Printf("Deleting %v (%+v)", srcRef.Path, src)
+			batch.Delete(srcRef)
+			if src.Invite != "" {
+				batch.Delete(client.Collection(db.InviteCollectionPath(comp)).Doc(src.Invite))
+			}
+		} else {
+			batch.Update(srcRef, []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: firestore.Delete}})
+		}
+		batch.Update(userRef, []firestore.Update{{Path: "team", Value: dstRef.ID}})
+	} else {
+		// Solo users' climbs are stored in their user docs, so move them to the team.
+		entry = db.TeamUser{Name: user.Name, Climbs: user.Climbs}
+		batch.Update(userRef, []firestore.Update{
+			{Path: "team", Value: dstRef.ID},
+			{Path: "climbs", Value: firestore.Delete},
+		})
+	}
+	batch.Update(dstRef, []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: nonNilClimbs(entry)}})
+
+	log.Printf("Moving user %v from team %q to %v", uid, user.Team, dstRef.Path)
+	if _, err := batch.Commit(ctx); err != nil {
+		http.Error(w, loc.T("Failed moving user: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Moved %q to team %q", entry.Name, dst.Name))
+}
+
+// handleRotateInvite handles a "rotateInvite" POST request.
+// It gives the team identified by the "team" parameter a new invite code and
+// deletes its old one.
+func handleRotateInvite(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	ref, team, err := findTeam(ctx, client, comp, r.FormValue("team"))
+	if err != nil {
+		writeTeamError(w, loc, err)
+		return
+	}
+	code, err := commitWithInvite(ctx, client, comp, ref.ID, func(code string, batch *firestore.WriteBatch) {
+		if team.Invite != "" {
+			batch.Delete(client.Collection(db.InviteCollectionPath(comp)).Doc(team.Invite))
+		}
+		batch.Update(ref, []firestore.Update{{Path: "invite", Value: code}})
+		log.Printf("Changing %v invite from %v to %v", ref.Path, team.Invite, code)
+	})
+	if err != nil {
+		http.Error(w, loc.T("Failed changing invite code: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, loc.T("Changed invite code for team %q from %v to %v", team.Name, team.Invite, code))
+}
+
+// errTeamNotFound is returned by findTeam if the requested team doesn't exist.
+var errTeamNotFound = errors.New("team not found")
+
+// findTeam returns competition comp's team identified by s, which may be either a
+// team ID or an invite code. errTeamNotFound is returned if the team doesn't exist.
+func findTeam(ctx context.Context, client *firestore.Client, comp, s string) (
+	*firestore.DocumentRef, *db.Team, error) {
+	if s == "" {
+		return nil, nil, errTeamNotFound
+	}
+
+	id := s
+	if len(s) == inviteCodeLength {
+		var invite db.Invite
+		ref := client.Collection(db.InviteCollectionPath(comp)).Doc(s)
+		if ok, err := getDocIfExists(ctx, ref, &invite); err != nil {
+			return nil, nil, err
+		} else if ok {
+			id = invite.Team
+		}
+	}
+
+	ref := client.Collection(db.TeamCollectionPath(comp)).Doc(id)
+	var team db.Team
+	if ok, err := getDocIfExists(ctx, ref, &team); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, errTeamNotFound
+	}
+	return ref, &team, nil
+}
+
+// writeTeamError writes an error returned by findTeam to w.
+func writeTeamError(w http.ResponseWriter, loc *localizer, err error) {
+	if err == errTeamNotFound {
+		http.Error(w, loc.T("Team not found"), http.StatusBadRequest)
+	} else {
+		http.Error(w, loc.T("Failed getting team: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// getDocIfExists is similar to db.GetDoc, but returns false instead of an error
+// if the doc doesn't exist.
+func getDocIfExists(ctx context.Context, ref *firestore.DocumentRef, out interface{}) (bool, error) {
+	snap, err := ref.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed getting snapshot for %v: %v", ref.Path, err)
+	}
+	if err := snap.DataTo(out); err != nil {
+		return false, fmt.Errorf("failed decoding %v: %v", ref.Path, err)
+	}
+	return true, nil
+}
+
+// getNameParam returns the trimmed "name" parameter from r.
+// An error is returned if the name isn't valid for a user or team.
+func getNameParam(r *http.Request) (string, error) {
+	return checkName(r.FormValue("name"))
+}
+
+// checkName returns name with leading and trailing whitespace trimmed.
+// An error is returned if the name isn't valid for a user or team.
+func checkName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name not supplied")
+	} else if utf8.RuneCountInString(name) > maxNameLength {
+		return "", fmt.Errorf("name is longer than %d characters", maxNameLength)
+	}
+	return name, nil
+}
+
+// activeMembers returns the number of team's members who haven't left it.
+func activeMembers(team *db.Team) int {
+	var n int
+	for _, u := range team.Users {
+		if !u.Left {
+			n++
+		}
+	}
+	return n
+}
+
+// nonNilClimbs returns a copy of u with a non-nil Climbs map, since
+// firestore.rules requires that team members' climbs be present.
+func nonNilClimbs(u db.TeamUser) db.TeamUser {
+	if u.Climbs == nil {
+		u.Climbs = make(map[string]db.ClimbState)
+	}
+	return u
+}
+
+// commitWithInvite creates a new invite doc in competition comp pointing at the
+// team with ID teamID, calls f to add additional writes to the same batch, and
+// commits the batch. If the randomly-chosen invite code is already in use, the
+// process is repeated with a different code. The new code is returned.
+func commitWithInvite(ctx context.Context, client *firestore.Client, comp, teamID string,
+	f func(code string, batch *firestore.WriteBatch)) (string, error) {
+	for i := 0; i < maxInviteAttempts; i++ {
+		code, err := newInviteCode()
+		if err != nil {
+			return "", err
+		}
+		batch := client.Batch()
+		// Create fails if the doc already exists, causing the whole batch to fail.
+		batch.Create(client.Collection(db.InviteCollectionPath(comp)).Doc(code), db.Invite{Team: teamID})
+		f(code, batch)
+		if _, err := batch.Commit(ctx); status.Code(err) == codes.AlreadyExists {
+			log.Printf("Invite code %v already in use", code)
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		return code, nil
+	}
+	return "", errors.New("couldn't find unused invite code")
+}
+
+// newInviteCode returns a random numeric invite code.
+func newInviteCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < inviteCodeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", inviteCodeLength, n), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestNewInviteCode(t *testing.T) {
+	re := regexp.MustCompile(`^\d{6}$`)
+	for i := 0; i < 100; i++ {
+		code, err := newInviteCode()
+		if err != nil {
+			t.Fatal("newInviteCode failed: ", err)
+		}
+		if !re.MatchString(code) {
+			t.Fatalf("newInviteCode returned %q", code)
+		}
+	}
+}
+
+func TestActiveMembers(t *testing.T) {
+	team := db.Team{Users: map[string]db.TeamUser{
+		"u1": {Name: "User 1"},
+		"u2": {Name: "User 2", Left: true},
+		"u3": {Name: "User 3"},
+	}}
+	if got := activeMembers(&team); got != 2 {
+		t.Errorf("activeMembers(%+v) = %v; want 2", team, got)
+	}
+	if got := activeMembers(&db.Team{}); got != 0 {
+		t.Errorf("activeMembers of empty team = %v; want 0", got)
+	}
+}
+
+// teamMember returns fake team data for a member named name.
+func teamMember(name string, left bool) map[string]interface{} {
+	m := map[string]interface{}{"name": name, "climbs": map[string]interface{}{"r1": 1}}
+	if left {
+		m["left"] = true
+	}
+	return m
+}
+
+func TestTeamSizeIncludesLeftMembers(t *testing.T) {
+	ff, client := newFakeFirestore(t, 0)
+	ff.set("global/config", map[string]interface{}{"teamSize": 3})
+	// Team 1 already has a member who left it.
+	ff.set("teams/t1", map[string]interface{}{"name": "Team 1", "users": map[string]interface{}{
+		"u1": teamMember("User 1", false),
+		"u2": teamMember("User 2", true),
+	}})
+	ff.set("teams/t2", map[string]interface{}{"name": "Team 2", "users": map[string]interface{}{
+		"u3": teamMember("User 3", false),
+		"u4": teamMember("User 4", false),
+	}})
+	ff.set("teams/t3", map[string]interface{}{"name": "Team 3", "users": map[string]interface{}{
+		"u5": teamMember("User 5", false),
+	}})
+	ff.set("users/u1", map[string]interface{}{"name": "User 1", "team": "t1"})
+	ff.set("users/u3", map[string]interface{}{"name": "User 3", "team": "t2"})
+	ff.set("users/u4", map[string]interface{}{"name": "User 4", "team": "t2"})
+	ff.set("users/u5", map[string]interface{}{"name": "User 5", "team": "t3"})
+	ff.set("users/u6", map[string]interface{}{"name": "User 6", "climbs": map[string]interface{}{}})
+	ff.set("users/u7", map[string]interface{}{"name": "User 7", "climbs": map[string]interface{}{}})
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	ctx := context.Background()
+	post := func(handler func(context.Context, http.ResponseWriter, *http.Request, *firestore.Client, string),
+		params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(ctx, w, req, client, db.DefaultCompetition)
+		return w
+	}
+	numUsers := func(tid string) int {
+		var team db.Team
+		if err := db.GetDoc(ctx, client.Doc("teams/"+tid), &team); err != nil {
+			t.Fatalf("Failed getting %v: %v", tid, err)
+		}
+		return len(team.Users)
+	}
+
+	// Team 1's two entries plus Team 2's two members would exceed the limit.
+	if w := post(handleMergeTeams, url.Values{"team": {"t1"}, "otherTeam": {"t2"}}); w.Code != 400 {
+		t.Errorf("Merging Team 2 into Team 1 returned %d; want 400", w.Code)
+	}
+	// Team 3 fills Team 1 up to the limit.
+	if w := post(handleMergeTeams, url.Values{"team": {"t1"}, "otherTeam": {"t3"}}); w.Code != 200 {
+		t.Fatalf("Merging Team 3 into Team 1 returned %d: %v", w.Code, w.Body.String())
+	}
+	if n := numUsers("t1"); n != 3 {
+		t.Errorf("Team 1 has %d user(s) after merge; want 3", n)
+	}
+	if w := post(handleMoveUser, url.Values{"user": {"u6"}, "team": {"t1"}}); w.Code != 400 {
+		t.Errorf("Moving user into full Team 1 returned %d; want 400", w.Code)
+	}
+
+	// Team 2 has room for one more member after u4 leaves it for a solo team.
+	ff.set("teams/t2", map[string]interface{}{"name": "Team 2", "users": map[string]interface{}{
+		"u3": teamMember("User 3", false),
+		"u4": teamMember("User 4", true),
+	}})
+	ff.set("users/u4", map[string]interface{}{"name": "User 4"})
+	if w := post(handleMoveUser, url.Values{"user": {"u6"}, "team": {"t2"}}); w.Code != 200 {
+		t.Fatalf("Moving user into Team 2 returned %d: %v", w.Code, w.Body.String())
+	}
+	if n := numUsers("t2"); n != 3 {
+		t.Errorf("Team 2 has %d user(s) after move; want 3", n)
+	}
+	if w := post(handleMoveUser, url.Values{"user": {"u7"}, "team": {"t2"}}); w.Code != 400 {
+		t.Errorf("Moving user into full Team 2 returned %d; want 400", w.Code)
+	}
+	// Errors should be localized.
+	if w := post(handleMoveUser, url.Values{"user": {"u7"}, "team": {"t2"}, "lang": {"es"}}); !strings.Contains(
+		w.Body.String(), `El equipo "Team 2" está lleno`) {
+		t.Errorf("Moving user into full Team 2 in Spanish returned %q", w.Body.String())
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+// handlePendingClimbs handles a "pendingClimbs" POST request.
+// It lists climbs of routes requiring verification that haven't been approved or rejected.
+func handlePendingClimbs(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	loc := newLocalizer(r)
+	var indexed db.IndexedData
+	if err := db.GetDoc(ctx, client.Doc(db.IndexedDataDocPath(comp)), &indexed); err != nil {
+		http.Error(w, loc.T("Failed getting indexed data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	teams := make(map[string]db.Team)
+	if err := loadCollection(ctx, client.Collection(db.TeamCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var t db.Team
+		err := snap.DataTo(&t)
+		teams[snap.Ref.ID] = t
+		return err
+	}); err != nil {
+		http.Error(w, loc.T("Failed loading teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	verifs, err := getVerifications(ctx, client, comp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pending := findPendingClimbs(teams, indexed.Routes, verifs)
+	fmt.Fprintln(w, loc.T("%d pending climb(s)", len(pending)))
+	for _, pc := range pending {
+		fmt.Fprintln(w, loc.T("%v (%q, team %q): %v %q %v", pc.user, pc.userName, pc.teamName,
+			pc.route, indexed.Routes[pc.route].Name, pc.state))
+	}
+}
+
+// handleApproveClimb handles an "approveClimb" POST request.
+// It approves the current climb of the route named by the "climbRoute" parameter
+// by the user named by the "climbUser" parameter. The "climbTeam" parameter is
+// handled as in handleUserClimbs.
+func handleApproveClimb(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	verifyClimb(ctx, w, r, client, comp, db.Approved)
+}
+
+// handleRejectClimb handles a "rejectClimb" POST request.
+// It is similar to handleApproveClimb, but rejects the climb instead.
+func handleRejectClimb(ctx context.Context, w http.ResponseWriter, r *http.Request, client *firestore.Client, comp string) {
+	verifyClimb(ctx, w, r, client, comp, db.Rejected)
+}
+
+// verifyClimb implements handleApproveClimb and handleRejectClimb.
+func verifyClimb(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	client *firestore.Client, comp string, status db.VerificationStatus) {
+	loc := newLocalizer(r)
+	uid := r.FormValue("climbUser")
+	rid := r.FormValue("climbRoute")
+
+	var indexed db.IndexedData
+	if err := db.GetDoc(ctx, client.Doc(db.IndexedDataDocPath(comp)), &indexed); err != nil {
+		http.Error(w, loc.T("Failed getting indexed data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if rt, ok := indexed.Routes[rid]; !ok {
+		http.Error(w, loc.T("Route %q not found", rid), http.StatusBadRequest)
+		return
+	} else if !rt.Verify {
+		http.Error(w, loc.T("Route %q doesn't require verification", rid), http.StatusBadRequest)
+		return
+	}
+
+	cloc, err := findClimbs(ctx, client, comp, uid, strings.TrimSpace(r.FormValue("climbTeam")), loc)
+	if err != nil {
+		writeClimbsError(w, loc, err)
+		return
+	}
+	state := cloc.climbs[rid]
+	if state == db.NotClimbed {
+		http.Error(w, loc.T("User %q hasn't climbed route %q", uid, rid), http.StatusBadRequest)
+		return
+	}
+
+	ref := client.Collection(db.VerificationCollectionPath(comp)).Doc(uid)
+	log.Printf("Setting %v's %v climb (%v) status to %v", uid, rid, state, status)
+	if _, err := ref.Set(ctx, map[string]interface{}{
+		"climbs": map[string]interface{}{
+			rid: map[string]interface{}{"state": state, "status": status, "time": time.Now()},
+		},
+	}, firestore.MergeAll); err != nil {
+		http.Error(w, loc.T("Failed writing %v: %v", ref.Path, err), http.StatusInternalServerError)
+		return
+	}
+	if err := invalidateStandings(ctx, client, comp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if status == db.Rejected {
+		fmt.Fprintln(w, loc.T("Rejected %v (%q) climb of %v (%v)", uid, cloc.name, rid, state))
+	} else {
+		fmt.Fprintln(w, loc.T("Approved %v (%q) climb of %v (%v)", uid, cloc.name, rid, state))
+	}
+}
+
+// getVerifications returns competition comp's verifications keyed by user ID.
+func getVerifications(ctx context.Context, client *firestore.Client, comp string) (
+	map[string]db.UserVerifications, error) {
+	verifs := make(map[string]db.UserVerifications)
+	if err := loadCollection(ctx, client.Collection(db.VerificationCollectionPath(comp)), func(snap *firestore.DocumentSnapshot) error {
+		var v db.UserVerifications
+		err := snap.DataTo(&v)
+		verifs[snap.Ref.ID] = v
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed loading verifications: %v", err)
+	}
+	return verifs, nil
+}
+
+// pendingClimb describes a climb awaiting verification.
+type pendingClimb struct {
+	user     string // user ID
+	userName string
+	teamName string
+	route    string // route ID
+	state    db.ClimbState
+}
+
+// findPendingClimbs returns the climbs in teams (keyed by team ID) of routes requiring
+// verification that haven't been approved or rejected in verifs (keyed by user ID).
+// The returned climbs are sorted by team name, user name, and route ID.
+func findPendingClimbs(teams map[string]db.Team, routes map[string]db.Route,
+	verifs map[string]db.UserVerifications) []pendingClimb {
+	var pending []pendingClimb
+	for _, team := range teams {
+		for uid, u := range team.Users {
+			for rid, state := range u.Climbs {
+				if state == db.NotClimbed || !routes[rid].Verify {
+					continue
+				}
+				if verifs[uid].Climbs[rid].GetStatus(state) == db.Pending {
+					pending = append(pending, pendingClimb{uid, u.Name, team.Name, rid, state})
+				}
+			}
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.teamName != b.teamName {
+			return a.teamName < b.teamName
+		}
+		if a.userName != b.userName {
+			return a.userName < b.userName
+		}
+		return a.route < b.route
+	})
+	return pending
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/derat/ascenso/go/db"
+)
+
+func TestFindPendingClimbs(t *testing.T) {
+	routes := map[string]db.Route{
+		"r1": {Name: "R1"},
+		"v1": {Name: "V1", Verify: true},
+		"v2": {Name: "V2", Verify: true},
+	}
+	teams := map[string]db.Team{
+		"t1": {Name: "B Team", Users: map[string]db.TeamUser{
+			"u1": {Name: "Ann", Climbs: map[string]db.ClimbState{"r1": db.Lead, "v1": db.Lead, "v2": db.TopRope}},
+			"u2": {Name: "Bob", Climbs: map[string]db.ClimbState{"v1": db.TopRope}},
+		}},
+		"t2": {Name: "A Team", Users: map[string]db.TeamUser{
+			"u3": {Name: "Cat", Climbs: map[string]db.ClimbState{"v2": db.Lead}},
+		}},
+	}
+	verifs := map[string]db.UserVerifications{
+		// u1's v1 climb was approved, but their v2 approval was for a different state.
+		"u1": {Climbs: map[string]db.Verification{
+			"v1": {State: db.Lead, Status: db.Approved},
+			"v2": {State: db.Lead, Status: db.Approved},
+		}},
+		// u2's v1 climb was rejected.
+		"u2": {Climbs: map[string]db.Verification{"v1": {State: db.TopRope, Status: db.Rejected}}},
+	}
+
+	got := findPendingClimbs(teams, routes, verifs)
+	want := []pendingClimb{
+		{"u3", "Cat", "A Team", "v2", db.Lead},
+		{"u1", "Ann", "B Team", "v2", db.TopRope},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findPendingClimbs(...) = %+v; want %+v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxContentType is the MIME type of XLSX files.
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// xlsxSheet describes a worksheet written by writeXLSX.
+type xlsxSheet struct {
+	name string // at most 31 characters
+	// rows contains the sheet's rows. The first row is displayed in bold.
+	// Cells must be strings or ints.
+	rows [][]interface{}
+}
+
+// writeXLSX writes a minimal Office Open XML workbook containing sheets to w.
+func writeXLSX(w io.Writer, sheets []xlsxSheet) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, f func(w *bufio.Writer) error) error {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		bw := bufio.NewWriter(fw)
+		bw.WriteString(xml.Header)
+		if err := f(bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+	str := func(s string) func(w *bufio.Writer) error {
+		return func(w *bufio.Writer) error {
+			_, err := w.WriteString(s)
+			return err
+		}
+	}
+
+	var types, wbSheets, wbRels strings.Builder
+	for i, sh := range sheets {
+		n := i + 1
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&wbSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sh.name), n, n)
+		fmt.Fprintf(&wbRels, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	stylesID := len(sheets) + 1
+
+	if err := add("[Content_Types].xml", str(
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`+
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`+
+			`<Default Extension="xml" ContentType="application/xml"/>`+
+			`<Override PartName="/xl/workbook.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`+
+			`<Override PartName="/xl/styles.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`+
+			types.String()+
+			`</Types>`)); err != nil {
+		return err
+	}
+	if err := add("_rels/.rels", str(
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" `+
+			`Target="xl/workbook.xml"/>`+
+			`</Relationships>`)); err != nil {
+		return err
+	}
+	if err := add("xl/workbook.xml", str(
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+			`<sheets>`+wbSheets.String()+`</sheets>`+
+			`</workbook>`)); err != nil {
+		return err
+	}
+	if err := add("xl/_rels/workbook.xml.rels", str(
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			wbRels.String()+
+			fmt.Sprintf(`<Relationship Id="rId%d" `+
+				`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" `+
+				`Target="styles.xml"/>`, stylesID)+
+			`</Relationships>`)); err != nil {
+		return err
+	}
+	// Style 0 is the default, and style 1 is bold (used for header rows).
+	if err := add("xl/styles.xml", str(
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font>`+
+			`<font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`+
+			`<fills count="2"><fill><patternFill patternType="none"/></fill>`+
+			`<fill><patternFill patternType="gray125"/></fill></fills>`+
+			`<borders count="1"><border/></borders>`+
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`+
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`+
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>`+
+			`</styleSheet>`)); err != nil {
+		return err
+	}
+	for i, sh := range sheets {
+		sh := sh
+		if err := add(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), func(w *bufio.Writer) error {
+			return writeXLSXSheet(w, &sh)
+		}); err != nil {
+			return fmt.Errorf("sheet %q: %v", sh.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// writeXLSXSheet writes the worksheet XML for sh to w.
+func writeXLSXSheet(w *bufio.Writer, sh *xlsxSheet) error {
+	w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(sh.rows) > 1 {
+		// Keep the header row visible while scrolling.
+		w.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+			`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+			`</sheetView></sheetViews>`)
+	}
+	w.WriteString(`<sheetData>`)
+	for i, row := range sh.rows {
+		fmt.Fprintf(w, `<row r="%d">`, i+1)
+		style := ""
+		if i == 0 {
+			style = ` s="1"`
+		}
+		for j, cell := range row {
+			ref := xlsxColumnName(j) + strconv.Itoa(i+1)
+			switch v := cell.(type) {
+			case string:
+				fmt.Fprintf(w, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+					ref, style, xmlEscape(v))
+			case int:
+				fmt.Fprintf(w, `<c r="%s"%s><v>%d</v></c>`, ref, style, v)
+			default:
+				return fmt.Errorf("cell %v has unsupported type %T", ref, cell)
+			}
+		}
+		w.WriteString(`</row>`)
+	}
+	w.WriteString(`</sheetData></worksheet>`)
+	return nil
+}
+
+// xlsxColumnName returns the spreadsheet name of the 0-indexed column i,
+// e.g. "A" for 0, "Z" for 25, and "AA" for 26.
+func xlsxColumnName(i int) string {
+	var name []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = append([]byte{byte('A' + (i-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// xmlEscape returns s escaped for use in XML character data or attribute values.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
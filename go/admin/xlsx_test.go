@@ -0,0 +1,102 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package admin
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestXLSXColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumnName(i); got != want {
+			t.Errorf("xlsxColumnName(%d) = %q; want %q", i, got, want)
+		}
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	sheets := []xlsxSheet{
+		{"First", [][]interface{}{{"name", "score"}, {"A & <B>", 12}, {" x ", -3}}},
+		{"Second", [][]interface{}{{"empty"}}},
+	}
+	var b bytes.Buffer
+	if err := writeXLSX(&b, sheets); err != nil {
+		t.Fatal("writeXLSX failed: ", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal("Failed opening zip: ", err)
+	}
+	files := make(map[string][]byte)
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed opening %v: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Failed reading %v: %v", f.Name, err)
+		}
+		// Check that each part is well-formed.
+		var v struct{}
+		if err := xml.Unmarshal(data, &v); err != nil {
+			t.Errorf("%v isn't well-formed: %v", f.Name, err)
+		}
+		files[f.Name] = data
+		names = append(names, f.Name)
+	}
+	if want := []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/sheet2.xml",
+	}; !reflect.DeepEqual(names, want) {
+		t.Errorf("writeXLSX wrote %q; want %q", names, want)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				String string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(files["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal("Failed parsing sheet1.xml: ", err)
+	}
+	var got [][]string
+	for _, row := range sheet.Rows {
+		var cells []string
+		for _, c := range row.Cells {
+			val := c.Value
+			if c.Type == "inlineStr" {
+				val = c.String
+			}
+			cells = append(cells, c.Ref+"="+val)
+		}
+		got = append(got, cells)
+	}
+	if want := [][]string{
+		{"A1=name", "B1=score"},
+		{"A2=A & <B>", "B2=12"},
+		{"A3= x ", "B3=-3"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sheet1.xml contains %q; want %q", got, want)
+	}
+}
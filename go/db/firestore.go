@@ -8,23 +8,80 @@ package db
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
 )
 
 const (
-	// Document paths in Cloud Firestore.
-	AuthDocPath        = "global/auth"
-	ConfigDocPath      = "global/config"
-	IndexedDataDocPath = "global/indexedData"
-	SortedDataDocPath  = "global/sortedData"
-
-	// Collection paths in Cloud Firestore.
-	InviteCollectionPath = "invites"
-	TeamCollectionPath   = "teams"
-	UserCollectionPath   = "users"
+	// AuthDocPath is the path of the Cloud Firestore doc containing the admin password hash.
+	// It's shared by all competitions.
+	AuthDocPath = "global/auth"
+
+	// CompetitionCollectionPath is the path of the collection containing per-competition docs.
+	// Each competition's data is nested under its doc, e.g. "competitions/2026/teams".
+	CompetitionCollectionPath = "competitions"
+
+	// ArchiveCollectionPath is the path of the collection containing archived competitions.
+	// It's shared by all competitions.
+	ArchiveCollectionPath = "archives"
+
+	// DefaultCompetition is the ID of the competition whose data is stored at the top level
+	// of the database rather than under CompetitionCollectionPath. This is the competition
+	// used by the web app.
+	DefaultCompetition = ""
 )
 
+// competitionIDRegexp matches valid non-default competition IDs.
+var competitionIDRegexp = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,64}$`)
+
+// ValidCompetitionID returns true if comp is DefaultCompetition or a valid competition ID.
+func ValidCompetitionID(comp string) bool {
+	return comp == DefaultCompetition || competitionIDRegexp.MatchString(comp)
+}
+
+// compPath returns the path of p within competition comp.
+func compPath(comp, p string) string {
+	if comp == DefaultCompetition {
+		return p
+	}
+	return CompetitionCollectionPath + "/" + comp + "/" + p
+}
+
+// Document paths in Cloud Firestore for competition comp.
+func ConfigDocPath(comp string) string      { return compPath(comp, "global/config") }
+func IndexedDataDocPath(comp string) string { return compPath(comp, "global/indexedData") }
+func SortedDataDocPath(comp string) string  { return compPath(comp, "global/sortedData") }
+
+// Collection paths in Cloud Firestore for competition comp.
+func InviteCollectionPath(comp string) string { return compPath(comp, "invites") }
+func TeamCollectionPath(comp string) string   { return compPath(comp, "teams") }
+func UserCollectionPath(comp string) string   { return compPath(comp, "users") }
+
+// CorrectionCollectionPath returns the path of competition comp's collection of
+// judges' corrections to climbs.
+func CorrectionCollectionPath(comp string) string { return compPath(comp, "corrections") }
+
+// StandingsDocPath returns the path of competition comp's doc containing precomputed
+// standings. It isn't under "global" since clients shouldn't be able to read it.
+func StandingsDocPath(comp string) string { return compPath(comp, "standings/current") }
+
+// StandingsTeamCollectionPath returns the path of the collection containing the
+// TeamChunk docs referenced by competition comp's Standings doc.
+func StandingsTeamCollectionPath(comp string) string {
+	return compPath(comp, "standings/current/teams")
+}
+
+// ArchiveTeamCollectionPath returns the path of the collection containing the
+// TeamChunk docs referenced by the Archive doc with the supplied ID.
+func ArchiveTeamCollectionPath(id string) string { return ArchiveCollectionPath + "/" + id + "/teams" }
+
+// VerificationCollectionPath returns the path of competition comp's collection of
+// judges' verifications of climbs. Docs are keyed by user ID.
+func VerificationCollectionPath(comp string) string { return compPath(comp, "verifications") }
+
 // GetDoc fetches a snapshot of the document at ref and decodes it into out,
 // which should be a pointer to a struct representing the document.
 func GetDoc(ctx context.Context, ref *firestore.DocumentRef, out interface{}) error {
@@ -38,22 +95,58 @@ func GetDoc(ctx context.Context, ref *firestore.DocumentRef, out interface{}) er
 	return nil
 }
 
+// DefaultTeamSize is the maximum number of members on a team if Config.TeamSize is unset.
+const DefaultTeamSize = 2
+
+// Config holds competition-wide settings.
+// It corresponds to the document at ConfigDocPath. The web app also reads
+// startTime and endTime fields that aren't used by this package.
+type Config struct {
+	// Readonly is true if users are prevented from modifying the database.
+	Readonly bool `firestore:"readonly"`
+	// TeamSize contains the maximum number of members on a team.
+	// If zero, DefaultTeamSize is used. Use GetTeamSize to get the actual size.
+	TeamSize int `firestore:"teamSize,omitempty"`
+	// PublicScoreboard is true if the scoreboard can be viewed without credentials.
+	PublicScoreboard bool `firestore:"publicScoreboard,omitempty"`
+	// AnonymizeScoreboard is true if climbers' names should be abbreviated to
+	// initials on the public scoreboard.
+	AnonymizeScoreboard bool `firestore:"anonymizeScoreboard,omitempty"`
+}
+
+// GetTeamSize returns the maximum number of members on a team.
+func (c *Config) GetTeamSize() int {
+	if c.TeamSize > 0 {
+		return c.TeamSize
+	}
+	return DefaultTeamSize
+}
+
 // SortedData holds sorted area and then route data.
 // This format is structured to be easy to display in the app's routes view.
 // It corresponds to the document at sortedDataDocPath.
 type SortedData struct {
 	// Areas contains areas in the order in which they were seen.
 	// Each area's Routes field contains routes in the order in which they were seen.
-	Areas []Area `firestore:"areas"`
+	Areas []Area `firestore:"areas" json:"areas" yaml:"areas"`
 }
 
 // newSortedData constructs a sortedData struct from the supplied areas and routes.
-// An error is returned if any areas don't contain routes or any routes
-// reference undefined areas.
+// An error is returned if any areas don't contain routes, any routes
+// reference undefined areas, or any routes contain invalid metadata.
 func NewSortedData(areas []Area, routes []Route) (SortedData, error) {
 	// Build a map from area ID to slice of routes, clearing area IDs as we go.
 	areaRoutes := make(map[string][]Route)
 	for _, r := range routes {
+		if !r.Type.Valid() {
+			return SortedData{}, fmt.Errorf("route %q has invalid type %q", r.ID, r.Type)
+		}
+		if r.Type == Boulder && r.Grade != "" && !IsVGrade(r.Grade) {
+			return SortedData{}, fmt.Errorf("boulder problem %q has non-V grade %q", r.ID, r.Grade)
+		}
+		if r.Pitches < 0 {
+			return SortedData{}, fmt.Errorf("route %q has invalid pitch count %d", r.ID, r.Pitches)
+		}
 		id := r.Area
 		r.Area = ""
 		areaRoutes[id] = append(areaRoutes[id], r)
@@ -83,6 +176,23 @@ func NewSortedData(areas []Area, routes []Route) (SortedData, error) {
 	return sd, nil
 }
 
+// Split returns the areas and routes in sd in the order in which they appear.
+// It is the inverse of NewSortedData: the areas' Routes fields are cleared and
+// each route's Area field is set to the ID of its containing area.
+func (sd *SortedData) Split() ([]Area, []Route) {
+	areas := make([]Area, 0, len(sd.Areas))
+	var routes []Route
+	for _, a := range sd.Areas {
+		for _, r := range a.Routes {
+			r.Area = a.ID
+			routes = append(routes, r)
+		}
+		a.Routes = nil
+		areas = append(areas, a)
+	}
+	return areas, routes
+}
+
 // IndexedData contains areas and routes optimized for lookup by ID.
 // It corresponds to the document at indexedDataDocPath.
 type IndexedData struct {
@@ -117,33 +227,85 @@ func NewIndexedData(areas []Area, routes []Route) IndexedData {
 // Area contains information about an area consisting of multiple routes.
 type Area struct {
 	// ID contains a short name uniquely identifying the area, e.g. "el_bloque".
-	ID string `firestore:"id,omitempty"`
+	ID string `firestore:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
 	// Name contains the full area name, e.g. "El Bloque".
-	Name string `firestore:"name"`
+	Name string `firestore:"name" json:"name" yaml:"name"`
 	// Routes optionally contains sorted routes.
-	Routes []Route `firestore:"routes,omitempty"`
+	Routes []Route `firestore:"routes,omitempty" json:"routes,omitempty" yaml:"routes,omitempty"`
 	// MPID contains the area's Mountain Project ID.
-	MPID string `firestore:"mpId,omitempty"`
+	MPID string `firestore:"mpId,omitempty" json:"mpId,omitempty" yaml:"mpId,omitempty"`
+	// Extra contains additional uninterpreted data keyed by lowercase column name,
+	// e.g. from unrecognized columns in uploaded CSV files.
+	Extra map[string]string `firestore:"extra,omitempty" json:"extra,omitempty" yaml:"extra,omitempty"`
 }
 
 // Route contains information about an individual route.
 type Route struct {
 	// ID contains a short name uniquely identifying the route, e.g. "night_vision".
-	ID string `firestore:"id,omitempty"`
+	ID string `firestore:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
 	// Name contains the full route name, e.g. "Night Vision".
-	Name string `firestore:"name"`
+	Name string `firestore:"name" json:"name" yaml:"name"`
 	// Area contains the ID of the area containing this route, i.e. area.ID.
-	Area string `firestore:"area,omitempty"`
-	// Grade contains the route's grade, e.g. "5.10b" or "5.11c/d".
-	Grade string `firestore:"grade,omitempty"`
+	Area string `firestore:"area,omitempty" json:"area,omitempty" yaml:"area,omitempty"`
+	// Grade contains the route's grade, e.g. "5.10b" or "5.11c/d" for roped routes
+	// or "V4" or "V5-6" for boulder problems.
+	Grade string `firestore:"grade,omitempty" json:"grade,omitempty" yaml:"grade,omitempty"`
 	// Lead contains the number of points awarded for leading the route.
-	Lead int `firestore:"lead,omitempty"`
+	Lead int `firestore:"lead,omitempty" json:"lead,omitempty" yaml:"lead,omitempty"`
 	// TR contains the number of points awarded for top-roping the route.
-	TR int `firestore:"tr,omitempty"`
+	TR int `firestore:"tr,omitempty" json:"tr,omitempty" yaml:"tr,omitempty"`
+	// Flash contains the number of points awarded for topping a boulder problem on the first attempt.
+	Flash int `firestore:"flash,omitempty" json:"flash,omitempty" yaml:"flash,omitempty"`
+	// Top contains the number of points awarded for topping a boulder problem after multiple attempts.
+	Top int `firestore:"top,omitempty" json:"top,omitempty" yaml:"top,omitempty"`
+	// Zone contains the number of points awarded for reaching a boulder problem's zone hold
+	// without topping it.
+	Zone int `firestore:"zone,omitempty" json:"zone,omitempty" yaml:"zone,omitempty"`
 	// MPID contains the route's Mountain Project ID.
-	MPID string `firestore:"mpId,omitempty"`
+	MPID string `firestore:"mpId,omitempty" json:"mpId,omitempty" yaml:"mpId,omitempty"`
 	// Route height in feet.
-	Height int `firestore:"height,omitempty"`
+	Height int `firestore:"height,omitempty" json:"height,omitempty" yaml:"height,omitempty"`
+	// Setter contains the name of the person who set the route.
+	Setter string `firestore:"setter,omitempty" json:"setter,omitempty" yaml:"setter,omitempty"`
+	// Color contains the route's hold or tape color, e.g. "red".
+	Color string `firestore:"color,omitempty" json:"color,omitempty" yaml:"color,omitempty"`
+	// Type contains the route's type. It may be empty if unknown.
+	Type RouteType `firestore:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	// Pitches contains the route's number of pitches. It may be 0 if unknown.
+	Pitches int `firestore:"pitches,omitempty" json:"pitches,omitempty" yaml:"pitches,omitempty"`
+	// Description contains a short description of the route to display in the route list.
+	Description string `firestore:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	// Verify is true if climbs of the route only count after they've been approved by a judge.
+	Verify bool `firestore:"verify,omitempty" json:"verify,omitempty" yaml:"verify,omitempty"`
+	// Extra contains additional uninterpreted data keyed by lowercase column name,
+	// e.g. from unrecognized columns in uploaded CSV files.
+	Extra map[string]string `firestore:"extra,omitempty" json:"extra,omitempty" yaml:"extra,omitempty"`
+}
+
+// RouteType describes the style of a route.
+type RouteType string
+
+const (
+	Sport   RouteType = "sport"
+	Trad    RouteType = "trad"
+	Boulder RouteType = "boulder"
+)
+
+// Valid returns true if t is empty or one of the known route types.
+func (t RouteType) Valid() bool {
+	switch t {
+	case "", Sport, Trad, Boulder:
+		return true
+	}
+	return false
+}
+
+// vGradeRegexp matches V-scale bouldering grades, e.g. "VB", "V0-", "V4", "V5+", or "V5-6".
+var vGradeRegexp = regexp.MustCompile(`^V(B|\d+([-+]|-\d+)?)$`)
+
+// IsVGrade returns true if grade is a V-scale bouldering grade.
+func IsVGrade(grade string) bool {
+	return vGradeRegexp.MatchString(grade)
 }
 
 // climbState describes whether and how a route was climbed.
@@ -153,8 +315,64 @@ const (
 	NotClimbed ClimbState = iota
 	Lead
 	TopRope
+	// Flash indicates that a boulder problem was topped on the first attempt.
+	Flash
+	// Top indicates that a boulder problem was topped after multiple attempts.
+	Top
+	// Zone indicates that a boulder problem's zone hold was reached but it wasn't topped.
+	Zone
 )
 
+// climbStateNames contains short names for ClimbState values.
+var climbStateNames = map[ClimbState]string{
+	NotClimbed: "none",
+	Lead:       "lead",
+	TopRope:    "tr",
+	Flash:      "flash",
+	Top:        "top",
+	Zone:       "zone",
+}
+
+// String returns a short name for s, e.g. "lead" or "tr".
+func (s ClimbState) String() string {
+	if name, ok := climbStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("ClimbState(%d)", int(s))
+}
+
+// ParseClimbState returns the ClimbState named name (see ClimbState.String).
+// Matching is case-insensitive. False is returned if name is unknown.
+func ParseClimbState(name string) (ClimbState, bool) {
+	for s, n := range climbStateNames {
+		if strings.EqualFold(n, name) {
+			return s, true
+		}
+	}
+	return NotClimbed, false
+}
+
+// ClimbAttempts returns the number of attempts counted for a climb in state s, given the
+// number of attempts n recorded by the climber (zero if unrecorded). Flashes always take
+// a single attempt and tops take at least two. Roped climbs don't count attempts.
+func ClimbAttempts(s ClimbState, n int) int {
+	switch s {
+	case Flash:
+		return 1
+	case Top:
+		if n < 2 {
+			return 2
+		}
+		return n
+	case Zone:
+		if n < 1 {
+			return 1
+		}
+		return n
+	}
+	return 0
+}
+
 // Team contains information about a team.
 // It correponds to documents in the collection at TeamCollectionPath.
 type Team struct {
@@ -163,12 +381,29 @@ type Team struct {
 	// Invite contains the team's invitation code.
 	Invite string `firestore:"invite"`
 	// Users contains information about the team's members, keyed by user ID.
-	Users map[string]struct {
-		// Name contains the user's name.
-		Name string `firestore:"name"`
-		// Climbs contains a map from route ID (see route.ID) to state.
-		Climbs map[string]ClimbState `firestore:"climbs"`
-	} `firestore:"users"`
+	Users map[string]TeamUser `firestore:"users"`
+}
+
+// TeamUser contains information about a member of a team.
+type TeamUser struct {
+	// Name contains the user's name.
+	Name string `firestore:"name"`
+	// Climbs contains a map from route ID (see route.ID) to state.
+	Climbs map[string]ClimbState `firestore:"climbs"`
+	// Attempts contains a map from boulder problem route ID to the number of attempts
+	// that the user took to top the problem or, if it wasn't topped, to reach its zone.
+	// See ClimbAttempts.
+	Attempts map[string]int `firestore:"attempts,omitempty"`
+	// Left is true if the user left the team after reporting climbs.
+	// Their climbs are still included in the team's score.
+	Left bool `firestore:"left,omitempty"`
+}
+
+// Invite contains information about a team invitation code.
+// It corresponds to documents in the collection at InviteCollectionPath, keyed by code.
+type Invite struct {
+	// Team contains the ID of the team that the code belongs to.
+	Team string `firestore:"team"`
 }
 
 // User contains information about a user.
@@ -178,6 +413,165 @@ type User struct {
 	Name string `firestore:"name"`
 	// Climbs contains the user's climbs. It's only used if the user isn't on a team.
 	Climbs map[string]ClimbState `firestore:"climbs"`
+	// Attempts contains the user's boulder attempts as described in TeamUser.Attempts.
+	// It's only used if the user isn't on a team.
+	Attempts map[string]int `firestore:"attempts,omitempty"`
 	// Team contains the user's team ID. It's empty if they aren't on a team.
 	Team string `firestore:"team"`
+	// Category contains the user's competition category, e.g. "Open" or "Youth".
+	// It's only set for users who were registered from a roster by an admin.
+	Category string `firestore:"category,omitempty"`
+}
+
+// Archive contains a frozen copy of a competition's final results.
+// It corresponds to documents in the collection at ArchiveCollectionPath.
+type Archive struct {
+	// Competition contains the ID of the archived competition.
+	Competition string `firestore:"competition"`
+	// Time contains the time at which the archive was created.
+	Time time.Time `firestore:"time"`
+	// SortedData contains the competition's areas and routes.
+	SortedData SortedData `firestore:"sortedData"`
+	// NumTeams contains the number of archived teams.
+	NumTeams int `firestore:"numTeams"`
+	// NumChunks contains the number of TeamChunk docs holding the competition's teams
+	// and their final standings. See TeamChunkID.
+	NumChunks int `firestore:"numChunks"`
+	// ChunkPrefix contains the prefix of the IDs of the TeamChunk docs in the collection
+	// at ArchiveTeamCollectionPath.
+	ChunkPrefix string `firestore:"chunkPrefix"`
+}
+
+// Standings contains a competition's precomputed standings.
+// It corresponds to the document at StandingsDocPath.
+type Standings struct {
+	// Time contains the time at which the standings were computed.
+	Time time.Time `firestore:"time"`
+	// NumChunks contains the number of TeamChunk docs holding the competition's
+	// non-empty teams and their standings. See TeamChunkID.
+	NumChunks int `firestore:"numChunks"`
+	// ChunkPrefix contains the prefix of the IDs of the TeamChunk docs in the collection
+	// at StandingsTeamCollectionPath.
+	ChunkPrefix string `firestore:"chunkPrefix"`
+	// LeaseExpiry contains the time until which a request has claimed the right to
+	// recompute the standings. Other requests use the existing standings until then.
+	LeaseExpiry time.Time `firestore:"leaseExpiry"`
+}
+
+// TeamChunk contains some of the teams from an Archive or Standings doc.
+// Teams are split across multiple docs to stay under Firestore's document size limit.
+type TeamChunk struct {
+	// Teams contains teams and their standings.
+	Teams []ArchivedTeam `firestore:"teams"`
+}
+
+// TeamChunkID returns the ID of the i-th (starting at 0) TeamChunk doc with the
+// supplied ID prefix.
+func TeamChunkID(prefix string, i int) string { return fmt.Sprintf("%s%04d", prefix, i) }
+
+// ArchivedTeam contains a team's final standing within an Archive.
+type ArchivedTeam struct {
+	// Name contains the team's name.
+	Name string `firestore:"name"`
+	// Score contains the team's total score.
+	Score int `firestore:"score"`
+	// NumClimbs contains the total number of routes climbed by the team's members.
+	NumClimbs int `firestore:"numClimbs"`
+	// Height contains the total height in feet climbed by the team's members.
+	Height int `firestore:"height"`
+	// Attempts contains the total number of boulder attempts made by the team's members.
+	Attempts int `firestore:"attempts,omitempty"`
+	// Users contains the team's members.
+	Users []ArchivedUser `firestore:"users"`
+}
+
+// ArchivedUser contains a climber's final standing within an ArchivedTeam.
+type ArchivedUser struct {
+	// UID contains the user's ID. It's only set in Standings chunks, since archives
+	// outlive the erasure of users' data.
+	UID string `firestore:"uid,omitempty"`
+	// Name contains the user's name.
+	Name string `firestore:"name"`
+	// Score contains the user's score.
+	Score int `firestore:"score"`
+	// NumClimbs contains the number of routes climbed by the user.
+	NumClimbs int `firestore:"numClimbs"`
+	// Height contains the total height in feet climbed by the user.
+	Height int `firestore:"height"`
+	// Attempts contains the total number of boulder attempts made by the user.
+	Attempts int `firestore:"attempts,omitempty"`
+	// Climbs contains a map from route ID (see route.ID) to state.
+	Climbs map[string]ClimbState `firestore:"climbs"`
+}
+
+// Correction describes a change to a user's climbs made by a judge.
+// It corresponds to documents in the collection at CorrectionCollectionPath.
+type Correction struct {
+	// Time contains the time at which the correction was made.
+	Time time.Time `firestore:"time"`
+	// User contains the ID of the user whose climbs were changed.
+	User string `firestore:"user"`
+	// Team contains the ID of the user's team, or is empty if they weren't on a team.
+	Team string `firestore:"team"`
+	// Route contains the ID of the route whose state was changed.
+	Route string `firestore:"route"`
+	// Old contains the route's previous state.
+	Old ClimbState `firestore:"old"`
+	// New contains the route's new state.
+	New ClimbState `firestore:"new"`
+	// Reason contains the judge's explanation for the change.
+	Reason string `firestore:"reason"`
+}
+
+// VerificationStatus describes a judge's decision about a climb of a route with Route.Verify set.
+type VerificationStatus int
+
+const (
+	// Pending indicates that a judge hasn't yet approved or rejected the climb.
+	Pending VerificationStatus = iota
+	// Approved indicates that a judge approved the climb. It counts toward the user's score.
+	Approved
+	// Rejected indicates that a judge rejected the climb. It doesn't count toward the user's score.
+	Rejected
+)
+
+// String returns a short name for s, e.g. "approved".
+func (s VerificationStatus) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Approved:
+		return "approved"
+	case Rejected:
+		return "rejected"
+	}
+	return fmt.Sprintf("VerificationStatus(%d)", int(s))
+}
+
+// UserVerifications contains judges' decisions about a user's climbs.
+// It corresponds to documents in the collection at VerificationCollectionPath.
+type UserVerifications struct {
+	// Climbs contains a map from route ID (see route.ID) to verification.
+	Climbs map[string]Verification `firestore:"climbs"`
+}
+
+// Verification describes a judge's decision about a user's climb of a route.
+type Verification struct {
+	// State contains the climb state that was verified. If the user later reports a
+	// different state for the route, the climb is pending again.
+	State ClimbState `firestore:"state"`
+	// Status contains the judge's decision.
+	Status VerificationStatus `firestore:"status"`
+	// Time contains the time at which the decision was made.
+	Time time.Time `firestore:"time"`
+}
+
+// GetStatus returns the verification status of a climb of a route in state s.
+// Pending is returned if the judge's decision was for a different state (including
+// if v is the zero value).
+func (v Verification) GetStatus(s ClimbState) VerificationStatus {
+	if v.State != s {
+		return Pending
+	}
+	return v.Status
 }
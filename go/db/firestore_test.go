@@ -0,0 +1,72 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package db
+
+import "testing"
+
+func TestCompetitionPaths(t *testing.T) {
+	for _, tc := range []struct {
+		comp   string
+		valid  bool
+		config string // expected ConfigDocPath
+		teams  string // expected TeamCollectionPath
+	}{
+		{DefaultCompetition, true, "global/config", "teams"},
+		{"spring-2026", true, "competitions/spring-2026/global/config", "competitions/spring-2026/teams"},
+		{"a/b", false, "", ""},
+		{"..", false, "", ""},
+		{"has space", false, "", ""},
+	} {
+		if valid := ValidCompetitionID(tc.comp); valid != tc.valid {
+			t.Errorf("ValidCompetitionID(%q) = %v; want %v", tc.comp, valid, tc.valid)
+		}
+		if !tc.valid {
+			continue
+		}
+		if p := ConfigDocPath(tc.comp); p != tc.config {
+			t.Errorf("ConfigDocPath(%q) = %q; want %q", tc.comp, p, tc.config)
+		}
+		if p := TeamCollectionPath(tc.comp); p != tc.teams {
+			t.Errorf("TeamCollectionPath(%q) = %q; want %q", tc.comp, p, tc.teams)
+		}
+	}
+}
+
+func TestParseClimbState(t *testing.T) {
+	for _, s := range []ClimbState{NotClimbed, Lead, TopRope, Flash, Top, Zone} {
+		if got, ok := ParseClimbState(s.String()); !ok || got != s {
+			t.Errorf("ParseClimbState(%q) = %v, %v; want %v, true", s.String(), got, ok, s)
+		}
+	}
+	if got, ok := ParseClimbState("TR"); !ok || got != TopRope {
+		t.Errorf(`ParseClimbState("TR") = %v, %v; want %v, true`, got, ok, TopRope)
+	}
+	if got, ok := ParseClimbState("bogus"); ok {
+		t.Errorf(`ParseClimbState("bogus") = %v, %v; want false`, got, ok)
+	}
+}
+
+func TestClimbAttempts(t *testing.T) {
+	for _, tc := range []struct {
+		state ClimbState
+		n     int
+		want  int
+	}{
+		{NotClimbed, 3, 0},
+		{Lead, 0, 0},
+		{TopRope, 2, 0},
+		{Flash, 0, 1},
+		{Flash, 3, 1},
+		{Top, 0, 2},
+		{Top, 1, 2},
+		{Top, 5, 5},
+		{Zone, 0, 1},
+		{Zone, 4, 4},
+	} {
+		if got := ClimbAttempts(tc.state, tc.n); got != tc.want {
+			t.Errorf("ClimbAttempts(%v, %d) = %d; want %d", tc.state, tc.n, got, tc.want)
+		}
+	}
+}
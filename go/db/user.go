@@ -0,0 +1,71 @@
+// Copyright 2026 Daniel Erat and Niniane Wang. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteUser adds writes to batch that delete competition comp's user doc for uid.
+// If the user is on a team, they're also removed from it as described in RemoveTeamUser.
+// The deleted user doc is returned, or nil if it doesn't exist (in which case no
+// writes are added). The returned bool is true if the user's team is also deleted.
+// At most three writes are added.
+func DeleteUser(ctx context.Context, client *firestore.Client, batch *firestore.WriteBatch,
+	comp, uid string) (*User, bool, error) {
+	userRef := client.Collection(UserCollectionPath(comp)).Doc(uid)
+	userSnap, err := userRef.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed getting %v: %v", userRef.Path, err)
+	}
+	var user User
+	if err := userSnap.DataTo(&user); err != nil {
+		return nil, false, fmt.Errorf("failed decoding %v: %v", userRef.Path, err)
+	}
+	log.Printf("Deleting user doc %v: %+v", userRef.Path, user)
+	batch.Delete(userRef)
+
+	var teamDeleted bool
+	if user.Team != "" {
+		teamRef := client.Collection(TeamCollectionPath(comp)).Doc(user.Team)
+		var team Team
+		if err := GetDoc(ctx, teamRef, &team); err != nil {
+			return nil, false, err
+		}
+		if _, ok := team.Users[uid]; !ok {
+			return nil, false, fmt.Errorf("user %v not on team %v", uid, teamRef.ID)
+		}
+		teamDeleted = RemoveTeamUser(client, batch, comp, teamRef, &team, uid)
+	}
+	return &user, teamDeleted, nil
+}
+
+// RemoveTeamUser adds writes to batch that remove user uid from team (located at teamRef
+// in competition comp). If the user was the team's only member, the team doc and the
+// corresponding invite doc are deleted instead and true is returned. At most two
+// writes are added.
+func RemoveTeamUser(client *firestore.Client, batch *firestore.WriteBatch, comp string,
+	teamRef *firestore.DocumentRef, team *Team, uid string) bool {
+	if len(team.Users) == 1 {
+		log.Printf("Deleting team doc %v: %+v", teamRef.Path, team)
+		batch.Delete(teamRef)
+
+		inviteRef := client.Collection(InviteCollectionPath(comp)).Doc(team.Invite)
+		log.Printf("Deleting invite doc %v", inviteRef.Path)
+		batch.Delete(inviteRef)
+		return true
+	}
+	log.Printf("Removing user from team doc %v: %+v", teamRef.Path, team)
+	batch.Update(teamRef, []firestore.Update{{FieldPath: firestore.FieldPath{"users", uid}, Value: firestore.Delete}})
+	return false
+}
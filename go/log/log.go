@@ -16,6 +16,8 @@ import (
 
 	"cloud.google.com/go/logging"
 	firebase "firebase.google.com/go"
+
+	"github.com/derat/ascenso/go/db"
 )
 
 const logName = "client" // Stackdriver log name
@@ -55,8 +57,9 @@ func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request)
 	// The client-supplied data is passed in a "data" property in a JSON object.
 	var body struct {
 		Data struct {
-			Records []logRecord `json:"records"`
-			Now     int64       `json:"now"`
+			Records     []logRecord `json:"records"`
+			Now         int64       `json:"now"`
+			Competition string      `json:"competition"`
 		} `json:"data"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -64,6 +67,12 @@ func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Failed reading body", http.StatusBadRequest)
 		return
 	}
+	comp := body.Data.Competition
+	if !db.ValidCompetitionID(comp) {
+		log.Printf("Bad competition %q", comp)
+		http.Error(w, "Bad competition", http.StatusBadRequest)
+		return
+	}
 
 	// Determine how far off client-supplied times are. This is a bit imprecise
 	// since it can't incorporate the time that the client spends calling us.
@@ -96,9 +105,10 @@ func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request)
 			Timestamp: time.Unix(0, rec.Time*int64(time.Millisecond)).Add(clientOffset),
 			Severity:  logging.ParseSeverity(rec.Severity),
 			Labels: map[string]string{
-				"code": rec.Code,
-				"uid":  uid,
-				"addr": getClientAddr(r),
+				"code":        rec.Code,
+				"uid":         uid,
+				"addr":        getClientAddr(r),
+				"competition": comp,
 			},
 			Payload: rec.Payload,
 		})
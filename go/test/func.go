@@ -16,8 +16,6 @@ import (
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"github.com/derat/ascenso/go/db"
 )
@@ -31,11 +29,17 @@ func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	comp := r.FormValue("competition")
+	if !db.ValidCompetitionID(comp) {
+		http.Error(w, fmt.Sprintf("Bad competition %q", comp), http.StatusBadRequest)
+		return
+	}
+
 	action := r.FormValue("action")
 	switch action {
 	case "deleteUser":
 		email := r.FormValue("email")
-		if err := deleteUser(ctx, email); err != nil {
+		if err := deleteUser(ctx, comp, email); err != nil {
 			log.Printf("Failed deleting %v: %v", email, err)
 			http.Error(w, fmt.Sprintf("Failed deleting %v: %v", email, err), http.StatusInternalServerError)
 		} else {
@@ -47,10 +51,10 @@ func HandleRequest(ctx context.Context, w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// deleteUser deletes the user doc corresponding to email.
+// deleteUser deletes competition comp's user doc corresponding to email.
 // It also removes the user from their team, if any, or deletes the whole team doc
 // and the corresponding invite doc if the user was the team's only member.
-func deleteUser(ctx context.Context, email string) error {
+func deleteUser(ctx context.Context, comp, email string) error {
 	if !isTestEmail(email) {
 		return errors.New("bad email address")
 	}
@@ -83,43 +87,12 @@ func deleteUser(ctx context.Context, email string) error {
 	// Create a batched write so we can atomically update multiple docs.
 	batch := client.Batch()
 
-	// Get the user doc from Firestore.
-	userRef := client.Collection(db.UserCollectionPath).Doc(uid)
-	userSnap, err := userRef.Get(ctx)
-	if status.Code(err) == codes.NotFound {
-		log.Printf("User doc %v doesn't exist; nothing to do", userRef.Path)
+	user, _, err := db.DeleteUser(ctx, client, batch, comp, uid)
+	if err != nil {
+		return err
+	} else if user == nil {
+		log.Printf("User doc for %v doesn't exist; nothing to do", uid)
 		return nil
-	} else if err != nil {
-		return fmt.Errorf("failed getting %v: %v", userRef.Path, err)
-	}
-	var user db.User
-	if err := userSnap.DataTo(&user); err != nil {
-		return fmt.Errorf("failed decoding %v: %v", userRef.Path, err)
-	}
-	log.Printf("Deleting user doc %v: %+v", userRef.Path, user)
-	batch.Delete(userRef)
-
-	if user.Team != "" {
-		// Get the team doc from Firestore.
-		teamRef := client.Collection(db.TeamCollectionPath).Doc(user.Team)
-		var team db.Team
-		if err := db.GetDoc(ctx, teamRef, &team); err != nil {
-			return err
-		}
-		if _, ok := team.Users[uid]; !ok {
-			return fmt.Errorf("user %v not on team %v", uid, teamRef.ID)
-		}
-		if len(team.Users) == 1 {
-			log.Printf("Deleting team doc %v: %+v", teamRef.Path, team)
-			batch.Delete(teamRef)
-
-			inviteRef := client.Collection(db.InviteCollectionPath).Doc(team.Invite)
-			log.Printf("Deleting invite doc %v", inviteRef.Path)
-			batch.Delete(inviteRef)
-		} else {
-			log.Printf("Removing user from team doc %v: %+v", teamRef.Path, team)
-			batch.Update(teamRef, []firestore.Update{{Path: "users." + uid, Value: firestore.Delete}})
-		}
 	}
 
 	if _, err := batch.Commit(ctx); err != nil {
@@ -85,3 +85,49 @@ describe('ClimbDropdown', () => {
     expect(button.classes(color)).toBe(false);
   });
 });
+
+describe('ClimbDropdown', () => {
+  it('offers boulder states and attempts', async () => {
+    const label = 'ABC';
+    const wrapper = factory({
+      state: ClimbState.NOT_CLIMBED,
+      boulder: true,
+      label,
+    });
+    const button = wrapper.findComponent({ name: 'v-btn' });
+    const clickItem = async (index: number) => {
+      button.trigger('click');
+      await flushPromises();
+      wrapper
+        .findAllComponents({ name: 'v-list-item' })
+        .at(index)
+        .vm.$emit('click');
+    };
+
+    // Boulder problems list flash, top, zone, and not-climbed items.
+    await clickItem(0);
+    await clickItem(1);
+    await clickItem(2);
+    expect(wrapper.emitted('update:state')).toEqual([
+      [ClimbState.FLASH],
+      [ClimbState.TOP],
+      [ClimbState.ZONE],
+    ]);
+
+    expect(button.text()).toEqual(label);
+    await wrapper.setProps({ state: ClimbState.FLASH });
+    expect(button.text()).toEqual('F');
+
+    // Tops count at least two attempts, and attempts can be added or removed
+    // via items after the state items.
+    await wrapper.setProps({ state: ClimbState.TOP });
+    expect(button.text()).toEqual('T2');
+    await clickItem(4);
+    await wrapper.setProps({ attempts: 5 });
+    expect(button.text()).toEqual('T5');
+    await clickItem(5);
+    await wrapper.setProps({ state: ClimbState.ZONE, attempts: undefined });
+    expect(button.text()).toEqual('Z1');
+    expect(wrapper.emitted('update:attempts')).toEqual([[3], [4]]);
+  });
+});
@@ -8,7 +8,12 @@ import { setUpVuetifyTesting, newVuetifyMountOptions } from '@/testutil';
 
 import ClimbDropdown from './ClimbDropdown.vue';
 import RouteList from './RouteList.vue';
-import { ClimbState, ClimberInfo, SetClimbStateEvent } from '@/models';
+import {
+  ClimbState,
+  ClimberInfo,
+  SetClimbAttemptsEvent,
+  SetClimbStateEvent,
+} from '@/models';
 
 setUpVuetifyTesting();
 
@@ -122,6 +127,29 @@ describe('RouteList', () => {
     ]);
   });
 
+  it('handles boulder problems', async () => {
+    const boulders = [
+      { id: 'b1', name: 'Boulder 1', grade: 'V3', flash: 12, top: 10, zone: 4 },
+    ];
+    const infos = [
+      new ClimberInfo('AB', { b1: ClimbState.TOP }, 'red', { b1: 3 }),
+    ];
+    await wrapper.setProps({ climberInfos: infos, routes: boulders });
+
+    expect(wrapper.findAll('.points').wrappers.map((w) => w.text())).toEqual([
+      '12 / 10 / 4',
+    ]);
+
+    const rd = getRouteDropdowns();
+    expect(rd[0][0].props('boulder')).toBe(true);
+    expect(rd[0][0].props('attempts')).toBe(3);
+
+    rd[0][0].vm.$emit('update:attempts', 4);
+    expect(wrapper.emitted('set-climb-attempts')).toEqual([
+      [new SetClimbAttemptsEvent(0, 'b1', 4)],
+    ]);
+  });
+
   it('deemphasizes filtered routes', async () => {
     // By default, no routes should be filtered out.
     const filtered = () =>
@@ -26,6 +26,20 @@ export const app = firebase.initializeApp({
   appId: process.env.VUE_APP_FIREBASE_APP_ID,
 });
 
+// ID of the competition whose data is used by the app. The default
+// competition (an empty ID) stores its data at the top level of the database,
+// while other competitions' data is nested under competitions/{id}. This
+// matches compPath in go/db/firestore.go.
+export const competition = process.env.VUE_APP_COMPETITION || '';
+
+// Returns the Firestore collection named |name| (e.g. 'users' or 'teams') for
+// |competition|.
+export function compCollection(name: string) {
+  return app
+    .firestore()
+    .collection(competition ? `competitions/${competition}/${name}` : name);
+}
+
 // Firestore persistence state.
 export enum FirestorePersistence {
   UNINITIALIZED,
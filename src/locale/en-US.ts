@@ -16,11 +16,19 @@ export default {
     updateReloadButton: 'Reload',
   },
   ClimbDropdown: {
+    addAttemptItem: 'Add attempt',
+    flashAbbrev: 'F',
+    flashItem: 'Flash',
     leadAbbrev: 'L',
     leadItem: 'Lead',
     notClimbedItem: 'Not climbed',
+    removeAttemptItem: 'Remove attempt',
+    topAbbrev: 'T',
+    topItem: 'Top',
     topRopeAbbrev: 'TR',
     topRopeItem: 'Top-rope',
+    zoneAbbrev: 'Z',
+    zoneItem: 'Zone',
   },
   Profile: {
     alreadyCreatingTeamError: 'Already creating team',
@@ -103,9 +111,11 @@ export default {
   },
   Statistics: {
     areasClimbedStat: 'Areas climbed',
+    attemptsStat: 'Boulder attempts',
     climbsCard: 'Climbs',
     failedLoadingRoutesError: 'Failed loading routes: {0}',
     failedLoadingUserOrTeamError: 'Failed loading user or team: {0}',
+    flashStat: 'Flash',
     heightStat: 'Height (feet)',
     imageClimbs: '0 climbs | 1 climb | {n} climbs',
     imagePoints: '0 points | 1 point | {n} points',
@@ -115,9 +125,11 @@ export default {
     otherCard: 'Other',
     pointsCard: 'Points',
     teamTab: 'Team',
+    topStat: 'Top',
     topRopeStat: 'Top-rope',
     totalClimbsStat: 'Total climbs',
     totalPointsStat: 'Total points',
+    zoneStat: 'Zones reached',
   },
   Toolbar: {
     buildText: 'Build {0}',
@@ -14,6 +14,9 @@ export default {
     updateReloadButton: 'Recargar',
   },
   ClimbDropdown: {
+    addAttemptItem: 'Añadir intento',
+    flashAbbrev: 'F',
+    flashItem: 'Flash',
     // TODO: http://www.notlostjustdiscovering.com/spanish-climbing-vocabulary/
     // says 'Lead' is 'Puntear', while
     // http://rlcolearnspanish.com/vocabulary/spanish-for-rock-climbers-theme-based-vocabulary-learning/
@@ -21,10 +24,15 @@ export default {
     leadAbbrev: 'L',
     leadItem: 'Lead',
     notClimbedItem: 'No escalado',
+    removeAttemptItem: 'Quitar intento',
+    topAbbrev: 'T',
+    topItem: 'Top',
     // TODO: https://www.reddit.com/r/climbing/comments/29laoe/climbing_vocabulary_in_spanish/
     // says 'Top-rope' is 'en yo-yo'.
     topRopeAbbrev: 'TR',
     topRopeItem: 'Top-rope',
+    zoneAbbrev: 'Z',
+    zoneItem: 'Zona',
   },
   Profile: {
     alreadyCreatingTeamError: 'Ya creando equipo',
@@ -108,9 +116,11 @@ export default {
   },
   Statistics: {
     areasClimbedStat: 'Áreas escaladas',
+    attemptsStat: 'Intentos de boulder',
     climbsCard: 'Rutas',
     failedLoadingRoutesError: 'Error en cargar las rutas: {0}',
     failedLoadingUserOrTeamError: 'Error en cargar usuario o equipo: {0}',
+    flashStat: 'Flash',
     heightStat: 'Altura (pies)',
     imageClimbs: '0 escaladas | 1 escalada | {n} escaladas',
     imagePoints: '0 puntos | 1 punto | {n} puntos',
@@ -120,9 +130,11 @@ export default {
     otherCard: 'Otro',
     pointsCard: 'Puntos',
     teamTab: 'Equipo',
+    topStat: 'Top',
     topRopeStat: 'Top-rope', // TODO: See ClimbDropdown above.
     totalClimbsStat: 'Total de rutas',
     totalPointsStat: 'Total de puntos',
+    zoneStat: 'Zonas alcanzadas',
   },
   Toolbar: {
     buildText: 'Versión {0}',
@@ -5,7 +5,7 @@
 import Vue from 'vue';
 import { Component, Watch } from 'vue-property-decorator';
 import { User, Team } from '@/models';
-import { app } from '@/firebase';
+import { app, compCollection } from '@/firebase';
 
 import type firebase from 'firebase';
 
@@ -46,7 +46,7 @@ export default class UserLoader extends Vue {
 
   mounted() {
     // Kick things off by loading the user doc.
-    this.userRef = app.firestore().collection('users').doc(this.user.uid);
+    this.userRef = compCollection('users').doc(this.user.uid);
     this.$bind('userDoc', this.userRef)
       .then((userSnap) => {
         // If the user isn't on a team, then we're done. Otherwise,
@@ -64,7 +64,7 @@ export default class UserLoader extends Vue {
     // When the team ID in the user doc changes, update the reference and
     // snapshot for the team document accordingly.
     if (this.userDoc.team) {
-      this.teamRef = app.firestore().collection('teams').doc(this.userDoc.team);
+      this.teamRef = compCollection('teams').doc(this.userDoc.team);
       this.$bind('teamDoc', this.teamRef)
         .then(() => {
           this.userLoaded = true;
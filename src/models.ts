@@ -9,6 +9,26 @@ export enum ClimbState {
   NOT_CLIMBED = 0,
   LEAD,
   TOP_ROPE,
+  FLASH, // boulder topped on first attempt
+  TOP, // boulder topped after multiple attempts
+  ZONE, // boulder zone hold reached without topping
+}
+
+// Returns the number of attempts counted for a climb in |state|, given the
+// number of attempts |n| recorded by the climber. Flashes always take a single
+// attempt and tops take at least two. Roped climbs don't count attempts. This
+// matches ClimbAttempts in go/db/firestore.go.
+export function getClimbAttempts(state: ClimbState, n?: number): number {
+  switch (state) {
+    case ClimbState.FLASH:
+      return 1;
+    case ClimbState.TOP:
+      return Math.max(n || 0, 2);
+    case ClimbState.ZONE:
+      return Math.max(n || 0, 1);
+    default:
+      return 0;
+  }
 }
 
 // ClimberInfo contains information about a climber.
@@ -18,7 +38,8 @@ export class ClimberInfo {
   constructor(
     public name: string,
     public states: Record<string, ClimbState>,
-    public color: string
+    public color: string,
+    public attempts: Record<string, number> = {} // keyed by route ID
   ) {
     this.initials = name
       .split(/\s+/, 3)
@@ -98,6 +119,17 @@ export class SetClimbStateEvent {
   ) {}
 }
 
+// SetClimbAttemptsEvent is emitted in a 'set-climb-attempts' event by the
+// RouteList component when the number of attempts for a boulder problem is
+// changed.
+export class SetClimbAttemptsEvent {
+  constructor(
+    public index: number,
+    public route: string,
+    public attempts: number
+  ) {}
+}
+
 // Area contains information about a climbing area.
 // It appears in the global/indexedData and global/sortedData Firestore docs.
 export interface Area {
@@ -116,8 +148,23 @@ export interface Route {
   area?: string; // only in indexedData
   lead: number;
   tr: number;
+  flash?: number; // only for boulder problems
+  top?: number; // only for boulder problems
+  zone?: number; // only for boulder problems
   mpId?: string; // Mountain Project ID
   height?: number; // in feet
+  setter?: string;
+  color?: string; // hold or tape color
+  type?: string; // 'sport', 'trad', or 'boulder'
+  pitches?: number;
+  description?: string;
+  verify?: boolean; // climbs only count after being approved by a judge
+}
+
+// Returns true if |route| is a boulder problem, i.e. it has the 'boulder' type
+// or awards points for flashes, tops, or zones.
+export function isBoulder(route: Route): boolean {
+  return route.type == 'boulder' || !!(route.flash || route.top || route.zone);
 }
 
 // IndexedData corresponds to the global/indexedData Firestore doc.
@@ -135,6 +182,7 @@ export interface SortedData {
 export interface Config {
   startTime?: firebase.firestore.Timestamp;
   endTime?: firebase.firestore.Timestamp;
+  teamSize?: number; // defaults to DefaultTeamSize
 }
 
 // Team represents a document in the 'teams' collection.
@@ -145,14 +193,24 @@ export interface Team {
   abandoned?: boolean; // only if a user left after climbs were recorded
 }
 
-// Number of members on a complete team.
-export const TeamSize = 2;
+// Default number of members on a complete team.
+export const DefaultTeamSize = 2;
+
+// Returns the number of members on a complete team according to |config|.
+export function getTeamSize(config: Partial<Config>): number {
+  return config.teamSize && config.teamSize > 0
+    ? config.teamSize
+    : DefaultTeamSize;
+}
 
 // TeamUserData represents a record in the 'users' field in a document in the
 // 'teams' collection.
 export interface TeamUserData {
   name: string;
   climbs: Record<string, ClimbState>;
+  // Attempts to top boulder problems (or reach their zones) keyed by route ID.
+  // See getClimbAttempts().
+  attempts?: Record<string, number>;
   left?: boolean; // only if the user left the team after it was abandoned
 }
 
@@ -161,6 +219,7 @@ export interface User {
   name: string;
   team?: string; // only if on a team
   filters?: UserFilterData; // only if non-empty
+  category?: string; // only if registered from a roster
 }
 
 // UserFilterData represents route filter data inside User.
@@ -71,6 +71,7 @@ describe('Profile', () => {
   async function init(userDoc: User, teamDoc?: Team) {
     MockFirebase.currentUser = new MockUser(userID, userName);
     MockFirebase.setDoc(userPath, userDoc);
+    MockFirebase.setDoc('global/config', {});
     if (teamDoc) {
       MockFirebase.setDoc(teamPath, teamDoc);
       MockFirebase.setDoc(invitePath, inviteDoc);
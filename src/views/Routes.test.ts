@@ -20,6 +20,7 @@ import flushPromises from 'flush-promises';
 import {
   ClimbState,
   ClimberInfo,
+  SetClimbAttemptsEvent,
   SetClimbStateEvent,
   SortedData,
   Team,
@@ -187,6 +188,38 @@ describe('Routes', () => {
     ]);
   });
 
+  it('updates climb attempts', async () => {
+    // Simulate the first climber recording attempts on the first route.
+    const routeLists = wrapper.findAllComponents(RouteList).wrappers;
+    routeLists[0].vm.$emit(
+      'set-climb-state',
+      new SetClimbStateEvent(0, 'r1', ClimbState.TOP)
+    );
+    routeLists[0].vm.$emit(
+      'set-climb-attempts',
+      new SetClimbAttemptsEvent(0, 'r1', 3)
+    );
+    await flushPromises();
+
+    const expected = JSON.parse(JSON.stringify(teamDoc));
+    expected.users[testUID].climbs.r1 = ClimbState.TOP;
+    expected.users[testUID].attempts = { r1: 3 };
+    expect(MockFirebase.getDoc(teamPath)).toEqual(expected);
+    expect(
+      routeLists[0].props('climberInfos').map((ci: ClimberInfo) => ci.attempts)
+    ).toEqual([{ r1: 3 }, {}]);
+
+    // Attempts should be dropped when the route is no longer a top or zone.
+    routeLists[0].vm.$emit(
+      'set-climb-state',
+      new SetClimbStateEvent(0, 'r1', ClimbState.FLASH)
+    );
+    await flushPromises();
+    expected.users[testUID].climbs.r1 = ClimbState.FLASH;
+    expected.users[testUID].attempts = {};
+    expect(MockFirebase.getDoc(teamPath)).toEqual(expected);
+  });
+
   it('displays filters dialog', async () => {
     const dialog = wrapper.findComponent({ ref: 'filtersDialog' });
     expect(getValue(dialog)).toBeFalsy();
@@ -83,4 +83,61 @@ describe('Statistics', () => {
       [new Statistic('Height (feet)', 20)],
     ]);
   });
+
+  it('displays statistics for boulder problems', async () => {
+    MockFirebase.setDoc('global/indexedData', {
+      routes: {
+        r1: { area: 'a1', lead: 10, tr: 5, height: 20 },
+        b1: { area: 'a2', flash: 12, top: 10, zone: 4, height: 12 },
+        b2: { area: 'a2', flash: 8, top: 6, zone: 2 },
+      },
+    });
+    MockFirebase.setDoc('teams/test-team', {
+      users: {
+        'test-user': {
+          name: 'Test Name',
+          climbs: { b1: ClimbState.FLASH, b2: ClimbState.ZONE },
+          attempts: { b2: 3 },
+        },
+        'other-uid': {
+          name: 'Another User',
+          climbs: { r1: ClimbState.LEAD, b1: ClimbState.TOP },
+          attempts: { b1: 4 },
+        },
+      },
+    });
+    await flushPromises();
+
+    const cardStats: Statistic[][] = wrapper
+      .findAllComponents(StatisticsList)
+      .wrappers.map((w) => w.props('items'));
+    expect(cardStats).toEqual([
+      // Team tab.
+      [new Statistic('Total points', 34)],
+      [
+        new Statistic('Total climbs', 3, [
+          new Statistic('Lead', 1),
+          new Statistic('Top-rope', 0),
+          new Statistic('Flash', 1),
+          new Statistic('Top', 1),
+        ]),
+        new Statistic('Zones reached', 1),
+        new Statistic('Boulder attempts', 8),
+        new Statistic('Areas climbed', 2),
+      ],
+      [new Statistic('Height (feet)', 44)],
+      // Individual tab. Zones don't count as climbs.
+      [new Statistic('Total points', 14)],
+      [
+        new Statistic('Total climbs', 1, [
+          new Statistic('Flash', 1),
+          new Statistic('Top', 0),
+        ]),
+        new Statistic('Zones reached', 1),
+        new Statistic('Boulder attempts', 4),
+        new Statistic('Areas climbed', 1),
+      ],
+      [new Statistic('Height (feet)', 12)],
+    ]);
+  });
 });